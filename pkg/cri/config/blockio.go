@@ -0,0 +1,177 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package config
+
+import (
+	"bufio"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// BlockIOClass is a single named class from a BlockIO.ConfigFile: an overall
+// cgroup blkio weight plus optional per-device weight and throttle
+// read/write bps/iops overrides, applied to containers carrying the
+// blockio.resources.beta.kubernetes.io/class annotation.
+type BlockIOClass struct {
+	Name                    string
+	Weight                  uint16
+	WeightDevice            map[string]uint16
+	ThrottleReadBpsDevice   map[string]uint64
+	ThrottleWriteBpsDevice  map[string]uint64
+	ThrottleReadIOPSDevice  map[string]uint64
+	ThrottleWriteIOPSDevice map[string]uint64
+}
+
+// blockIODeviceKeys are the per-device keys recognized in a class section,
+// mapping each key to the BlockIOClass field it populates.
+var blockIODeviceKeys = map[string]func(*BlockIOClass) *map[string]uint64{
+	"throttle-read-bps":   func(c *BlockIOClass) *map[string]uint64 { return &c.ThrottleReadBpsDevice },
+	"throttle-write-bps":  func(c *BlockIOClass) *map[string]uint64 { return &c.ThrottleWriteBpsDevice },
+	"throttle-read-iops":  func(c *BlockIOClass) *map[string]uint64 { return &c.ThrottleReadIOPSDevice },
+	"throttle-write-iops": func(c *BlockIOClass) *map[string]uint64 { return &c.ThrottleWriteIOPSDevice },
+}
+
+// ParseBlockIOClasses parses a BlockIO.ConfigFile's contents. The format is
+// a simple INI-style file: one `[classname]` section per blockio class,
+// containing `weight = N`, and device-keyed settings as comma-separated
+// `device:value` pairs, e.g.:
+//
+//	[gold]
+//	weight = 500
+//	device-weight = sda:400,sdb:600
+//	throttle-read-bps = sda:1048576
+//
+// Blank lines and lines starting with '#' are ignored.
+func ParseBlockIOClasses(data []byte) (map[string]BlockIOClass, error) {
+	classes := map[string]BlockIOClass{}
+	var current *BlockIOClass
+
+	scanner := bufio.NewScanner(strings.NewReader(string(data)))
+	for lineNum := 1; scanner.Scan(); lineNum++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if strings.HasPrefix(line, "[") {
+			name := strings.TrimSpace(strings.TrimSuffix(strings.TrimPrefix(line, "["), "]"))
+			if name == "" {
+				return nil, fmt.Errorf("line %d: empty class name", lineNum)
+			}
+			classes[name] = BlockIOClass{Name: name}
+			c := classes[name]
+			current = &c
+			continue
+		}
+		if current == nil {
+			return nil, fmt.Errorf("line %d: setting %q outside of any [class] section", lineNum, line)
+		}
+		key, value, ok := splitSysconfigLine(line)
+		if !ok {
+			return nil, fmt.Errorf("line %d: malformed setting %q", lineNum, line)
+		}
+		key = strings.TrimSpace(key)
+		switch key {
+		case "weight":
+			w, err := strconv.ParseUint(value, 10, 16)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: invalid weight %q: %w", lineNum, value, err)
+			}
+			current.Weight = uint16(w)
+		case "device-weight":
+			devices, err := parseDeviceUintMap(value, 16)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", lineNum, err)
+			}
+			current.WeightDevice = toUint16Map(devices)
+		default:
+			fieldFn, ok := blockIODeviceKeys[key]
+			if !ok {
+				return nil, fmt.Errorf("line %d: unrecognized key %q", lineNum, key)
+			}
+			devices, err := parseDeviceUintMap(value, 64)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: %w", lineNum, err)
+			}
+			*fieldFn(current) = devices
+		}
+		classes[current.Name] = *current
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return classes, nil
+}
+
+// parseDeviceUintMap parses a comma-separated `device:value` list into a map.
+func parseDeviceUintMap(s string, bitSize int) (map[string]uint64, error) {
+	out := map[string]uint64{}
+	for _, pair := range strings.Split(s, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		parts := strings.SplitN(pair, ":", 2)
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("invalid device entry %q, want device:value", pair)
+		}
+		v, err := strconv.ParseUint(strings.TrimSpace(parts[1]), 10, bitSize)
+		if err != nil {
+			return nil, fmt.Errorf("invalid value for device %q: %w", parts[0], err)
+		}
+		out[strings.TrimSpace(parts[0])] = v
+	}
+	return out, nil
+}
+
+func toUint16Map(in map[string]uint64) map[string]uint16 {
+	out := make(map[string]uint16, len(in))
+	for k, v := range in {
+		out[k] = uint16(v)
+	}
+	return out
+}
+
+// DiffDeviceSets compares the previously resolved set of block devices
+// (e.g. from /sys/block) against a freshly rescanned set, returning which
+// device names were added and removed. Both results are sorted for
+// deterministic output. An empty diff means the device set is unchanged and
+// live containers' blkio throttling does not need to be re-resolved.
+func DiffDeviceSets(old, new []string) (added, removed []string) {
+	oldSet := make(map[string]bool, len(old))
+	for _, d := range old {
+		oldSet[d] = true
+	}
+	newSet := make(map[string]bool, len(new))
+	for _, d := range new {
+		newSet[d] = true
+	}
+	for _, d := range new {
+		if !oldSet[d] {
+			added = append(added, d)
+		}
+	}
+	for _, d := range old {
+		if !newSet[d] {
+			removed = append(removed, d)
+		}
+	}
+	sort.Strings(added)
+	sort.Strings(removed)
+	return added, removed
+}
@@ -0,0 +1,96 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package config
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseBlockIOClasses(t *testing.T) {
+	data := []byte(`
+# gold tier
+[gold]
+weight = 500
+device-weight = sda:400,sdb:600
+throttle-read-bps = sda:1048576
+throttle-write-iops = sda:500
+
+[silver]
+weight = 100
+`)
+	classes, err := ParseBlockIOClasses(data)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(classes) != 2 {
+		t.Fatalf("expected 2 classes, got %d", len(classes))
+	}
+	gold := classes["gold"]
+	if gold.Weight != 500 {
+		t.Fatalf("expected weight 500, got %d", gold.Weight)
+	}
+	if !reflect.DeepEqual(gold.WeightDevice, map[string]uint16{"sda": 400, "sdb": 600}) {
+		t.Fatalf("unexpected device weights: %v", gold.WeightDevice)
+	}
+	if !reflect.DeepEqual(gold.ThrottleReadBpsDevice, map[string]uint64{"sda": 1048576}) {
+		t.Fatalf("unexpected read bps throttle: %v", gold.ThrottleReadBpsDevice)
+	}
+	if !reflect.DeepEqual(gold.ThrottleWriteIOPSDevice, map[string]uint64{"sda": 500}) {
+		t.Fatalf("unexpected write iops throttle: %v", gold.ThrottleWriteIOPSDevice)
+	}
+	if classes["silver"].Weight != 100 {
+		t.Fatalf("expected silver weight 100, got %d", classes["silver"].Weight)
+	}
+}
+
+func TestParseBlockIOClassesSettingOutsideSection(t *testing.T) {
+	if _, err := ParseBlockIOClasses([]byte("weight = 500\n")); err == nil {
+		t.Fatal("expected error for setting outside any class section")
+	}
+}
+
+func TestParseBlockIOClassesUnrecognizedKey(t *testing.T) {
+	data := []byte("[gold]\nbogus = 1\n")
+	if _, err := ParseBlockIOClasses(data); err == nil {
+		t.Fatal("expected error for unrecognized key")
+	}
+}
+
+func TestParseBlockIOClassesInvalidDeviceEntry(t *testing.T) {
+	data := []byte("[gold]\ndevice-weight = sda-no-colon\n")
+	if _, err := ParseBlockIOClasses(data); err == nil {
+		t.Fatal("expected error for malformed device entry")
+	}
+}
+
+func TestDiffDeviceSets(t *testing.T) {
+	added, removed := DiffDeviceSets([]string{"sda", "sdb"}, []string{"sdb", "sdc"})
+	if !reflect.DeepEqual(added, []string{"sdc"}) {
+		t.Fatalf("unexpected added: %v", added)
+	}
+	if !reflect.DeepEqual(removed, []string{"sda"}) {
+		t.Fatalf("unexpected removed: %v", removed)
+	}
+}
+
+func TestDiffDeviceSetsNoChange(t *testing.T) {
+	added, removed := DiffDeviceSets([]string{"sda"}, []string{"sda"})
+	if len(added) != 0 || len(removed) != 0 {
+		t.Fatalf("expected no diff, got added=%v removed=%v", added, removed)
+	}
+}
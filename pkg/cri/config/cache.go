@@ -0,0 +1,181 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package config
+
+import (
+	"sync"
+	"sync/atomic"
+)
+
+// CacheStats holds the pull-through mirror cache counters the image service
+// exposes as the cache_hit_bytes/cache_miss_bytes/cache_evictions_total metrics.
+type CacheStats struct {
+	HitBytes       int64
+	MissBytes      int64
+	EvictionsTotal int64
+}
+
+// cacheEntry is a single digest's position in the LRU eviction queue.
+type cacheEntry struct {
+	digest string
+	size   int64
+}
+
+// MirrorCache is an in-memory LRU index over a Mirror's local
+// content-addressable cache directory, keyed by digest. It tracks current
+// occupancy against Cache.MaxSize, reports which digests the caller should
+// remove from disk once MaxSize is exceeded, and coordinates concurrent
+// fetches of the same digest so a Shared cache hits the upstream at most
+// once per digest. The actual cache directory layout, content-addressable
+// storage, and file-lock coordination across a shared host pool belong to
+// the CRI image service that owns the cache directory; MirrorCache is the
+// bookkeeping that service calls into.
+type MirrorCache struct {
+	cfg Cache
+
+	mu       sync.Mutex
+	order    []*cacheEntry // index 0 is least recently used
+	byDigest map[string]*cacheEntry
+	size     int64
+
+	Stats CacheStats
+
+	fetchMu  sync.Mutex
+	inFlight map[string]*fetchWaiter
+}
+
+// fetchWaiter tracks the in-flight fetch of a single digest and how many
+// other callers are currently blocked on it.
+type fetchWaiter struct {
+	wg      sync.WaitGroup
+	waiters int32 // accessed atomically
+}
+
+// NewMirrorCache creates a MirrorCache governed by cfg's MaxSize and Shared settings.
+func NewMirrorCache(cfg Cache) *MirrorCache {
+	return &MirrorCache{
+		cfg:      cfg,
+		byDigest: make(map[string]*cacheEntry),
+		inFlight: make(map[string]*fetchWaiter),
+	}
+}
+
+// Hit records a cache hit for digest of the given size, moving it to the
+// most-recently-used position and adding to the hit metric. Hit is a no-op
+// on occupancy accounting for digests Admit has not already recorded.
+func (c *MirrorCache) Hit(digest string, size int64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.Stats.HitBytes += size
+	if e, ok := c.byDigest[digest]; ok {
+		c.touch(e)
+	}
+}
+
+// Admit records digest as freshly fetched from the upstream mirror (a miss)
+// with the given size, inserting or updating it at the most-recently-used
+// position and evicting least-recently-used entries until occupancy is back
+// under Cache.MaxSize. It returns the digests the caller should remove from
+// the cache directory. A non-positive Cache.MaxSize disables eviction.
+func (c *MirrorCache) Admit(digest string, size int64) []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.Stats.MissBytes += size
+	if e, ok := c.byDigest[digest]; ok {
+		c.size += size - e.size
+		e.size = size
+		c.touch(e)
+		return c.evict()
+	}
+	e := &cacheEntry{digest: digest, size: size}
+	c.byDigest[digest] = e
+	c.order = append(c.order, e)
+	c.size += size
+	return c.evict()
+}
+
+// touch moves e to the most-recently-used end of the order slice.
+func (c *MirrorCache) touch(e *cacheEntry) {
+	for i, o := range c.order {
+		if o == e {
+			c.order = append(c.order[:i], c.order[i+1:]...)
+			break
+		}
+	}
+	c.order = append(c.order, e)
+}
+
+// evict removes least-recently-used entries until size is back under
+// cfg.MaxSize, returning the evicted digests in eviction order.
+func (c *MirrorCache) evict() []string {
+	if c.cfg.MaxSize <= 0 {
+		return nil
+	}
+	var evicted []string
+	for c.size > c.cfg.MaxSize && len(c.order) > 0 {
+		e := c.order[0]
+		c.order = c.order[1:]
+		delete(c.byDigest, e.digest)
+		c.size -= e.size
+		c.Stats.EvictionsTotal++
+		evicted = append(evicted, e.digest)
+	}
+	return evicted
+}
+
+// Coordinate serializes concurrent fetches of the same digest when
+// Cache.Shared is set. The first caller for a digest gets alreadyFetching
+// false and must call release once its fetch completes. Every concurrent
+// caller for the same digest blocks in Coordinate until release is called,
+// then returns alreadyFetching true so it knows to read the now-populated
+// cache entry instead of hitting the upstream mirror itself. When Shared is
+// false, Coordinate is a no-op: every caller proceeds to fetch independently.
+func (c *MirrorCache) Coordinate(digest string) (alreadyFetching bool, release func()) {
+	if !c.cfg.Shared {
+		return false, func() {}
+	}
+	c.fetchMu.Lock()
+	fw, inFlight := c.inFlight[digest]
+	if inFlight {
+		atomic.AddInt32(&fw.waiters, 1)
+		c.fetchMu.Unlock()
+		fw.wg.Wait()
+		return true, func() {}
+	}
+	fw = &fetchWaiter{}
+	fw.wg.Add(1)
+	c.inFlight[digest] = fw
+	c.fetchMu.Unlock()
+	return false, func() {
+		c.fetchMu.Lock()
+		delete(c.inFlight, digest)
+		c.fetchMu.Unlock()
+		fw.wg.Done()
+	}
+}
+
+// Waiters reports how many callers are currently blocked in Coordinate for
+// digest, waiting on an in-flight fetch started by another caller.
+func (c *MirrorCache) Waiters(digest string) int {
+	c.fetchMu.Lock()
+	fw, ok := c.inFlight[digest]
+	c.fetchMu.Unlock()
+	if !ok {
+		return 0
+	}
+	return int(atomic.LoadInt32(&fw.waiters))
+}
@@ -0,0 +1,123 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package config
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestMirrorCacheAdmitEvictsLeastRecentlyUsed(t *testing.T) {
+	c := NewMirrorCache(Cache{MaxSize: 10})
+
+	if evicted := c.Admit("sha256:a", 4); len(evicted) != 0 {
+		t.Fatalf("unexpected eviction: %v", evicted)
+	}
+	if evicted := c.Admit("sha256:b", 4); len(evicted) != 0 {
+		t.Fatalf("unexpected eviction: %v", evicted)
+	}
+	// Touch "a" so "b" becomes least-recently-used.
+	c.Hit("sha256:a", 4)
+	// Admitting "c" pushes occupancy to 12 > MaxSize 10, which must evict "b".
+	evicted := c.Admit("sha256:c", 4)
+	if len(evicted) != 1 || evicted[0] != "sha256:b" {
+		t.Fatalf("expected sha256:b to be evicted, got %v", evicted)
+	}
+	if c.Stats.EvictionsTotal != 1 {
+		t.Fatalf("expected 1 eviction recorded, got %d", c.Stats.EvictionsTotal)
+	}
+	if c.Stats.MissBytes != 12 {
+		t.Fatalf("expected 12 miss bytes recorded, got %d", c.Stats.MissBytes)
+	}
+	if c.Stats.HitBytes != 4 {
+		t.Fatalf("expected 4 hit bytes recorded, got %d", c.Stats.HitBytes)
+	}
+}
+
+func TestMirrorCacheAdmitUnboundedWhenMaxSizeNotSet(t *testing.T) {
+	c := NewMirrorCache(Cache{})
+	for i := 0; i < 5; i++ {
+		if evicted := c.Admit("sha256:x", 1<<20); len(evicted) != 0 {
+			t.Fatalf("unbounded cache must never evict, got %v", evicted)
+		}
+	}
+}
+
+func TestMirrorCacheAdmitUpdatesExistingDigestSize(t *testing.T) {
+	c := NewMirrorCache(Cache{MaxSize: 100})
+	c.Admit("sha256:a", 10)
+	c.Admit("sha256:a", 20)
+	if got := c.Admit("sha256:b", 0); len(got) != 0 {
+		t.Fatalf("unexpected eviction: %v", got)
+	}
+	// Re-admitting "a" must have replaced its size (10 -> 20), not added to it.
+	if c.size != 20 {
+		t.Fatalf("expected occupancy 20 after resized re-admit, got %d", c.size)
+	}
+}
+
+func TestMirrorCacheCoordinateSharedDedupesConcurrentFetch(t *testing.T) {
+	c := NewMirrorCache(Cache{Shared: true})
+
+	alreadyFetching, release := c.Coordinate("sha256:a")
+	if alreadyFetching {
+		t.Fatal("first caller should not see alreadyFetching")
+	}
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	var second bool
+	go func() {
+		defer wg.Done()
+		second, _ = c.Coordinate("sha256:a")
+	}()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for c.Waiters("sha256:a") == 0 {
+		if time.Now().After(deadline) {
+			t.Fatal("timed out waiting for second caller to register as waiting")
+		}
+		time.Sleep(time.Millisecond)
+	}
+
+	release()
+	wg.Wait()
+
+	if !second {
+		t.Fatal("second concurrent caller for the same digest should see alreadyFetching")
+	}
+
+	// Once released, a fresh fetch for the same digest starts anew.
+	alreadyFetching, _ = c.Coordinate("sha256:a")
+	if alreadyFetching {
+		t.Fatal("a new fetch after release should not see alreadyFetching")
+	}
+}
+
+func TestMirrorCacheCoordinateNotSharedNeverDedupes(t *testing.T) {
+	c := NewMirrorCache(Cache{Shared: false})
+	alreadyFetching, release := c.Coordinate("sha256:a")
+	if alreadyFetching {
+		t.Fatal("non-shared cache must never report alreadyFetching")
+	}
+	release()
+	alreadyFetching, _ = c.Coordinate("sha256:a")
+	if alreadyFetching {
+		t.Fatal("non-shared cache must never report alreadyFetching")
+	}
+}
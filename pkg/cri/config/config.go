@@ -21,6 +21,8 @@ import (
 	"errors"
 	"fmt"
 	"net/url"
+	"os"
+	"strings"
 	"time"
 
 	"github.com/containerd/containerd/log"
@@ -68,7 +70,7 @@ type Runtime struct {
 	Options map[string]interface{} `toml:"options" json:"options"`
 	// PrivilegedWithoutHostDevices overloads the default behaviour for adding host devices to the
 	// runtime spec when the container is privileged. Defaults to false.
-	PrivilegedWithoutHostDevices bool `toml:"privileged_without_host_devices" json:"privileged_without_host_devices"`
+	PrivilegedWithoutHostDevices bool `toml:"privileged_without_host_devices" json:"privileged_without_host_devices" reload:"hot"`
 	// PrivilegedWithoutHostDevicesAllDevicesAllowed overloads the default behaviour device allowlisting when
 	// to the runtime spec when the container when PrivilegedWithoutHostDevices is already enabled. Requires
 	// PrivilegedWithoutHostDevices to be enabled. Defaults to false.
@@ -90,7 +92,41 @@ type Runtime struct {
 	// This features requires experimental CRI server to be enabled (use ENABLE_CRI_SANDBOXES=1)
 	// shim - means use whatever Controller implementation provided by shim (e.g. use RemoteController).
 	// podsandbox - means use Controller implementation from sbserver podsandbox package.
-	SandboxMode string `toml:"sandbox_mode" json:"sandboxMode"`
+	SandboxMode string `toml:"sandbox_mode" json:"sandboxMode" reload:"hot"`
+	// PidsLimit is the default maximum number of pids allowed in a container created with
+	// this runtime, overridable per-container via CRI's LinuxContainerResources. A value of
+	// -1 (the default) means unlimited.
+	PidsLimit int64 `toml:"pids_limit" json:"pidsLimit"`
+	// SignaturePolicyAnnotations is a list of scope annotations that must be satisfied
+	// by an image signature policy stricter than the cluster default before a pod may
+	// be scheduled onto this runtime class (e.g. requiring `signedBy` for confidential
+	// containers runtimes).
+	SignaturePolicyAnnotations []string `toml:"signature_policy_annotations" json:"signaturePolicyAnnotations"`
+	// MaxConcurrentDownloads restricts the number of concurrent downloads for each image
+	// pulled for a sandbox scheduled onto this runtime class. Overrides the PluginConfig
+	// default of the same name. Zero means the PluginConfig default applies.
+	MaxConcurrentDownloads int `toml:"max_concurrent_downloads" json:"maxConcurrentDownloads"`
+	// ImagePullProgressTimeout overrides the PluginConfig default of the same name for
+	// sandboxes scheduled onto this runtime class. The string is in the golang duration
+	// format, see: https://golang.org/pkg/time/#ParseDuration. Empty means the
+	// PluginConfig default applies.
+	ImagePullProgressTimeout string `toml:"image_pull_progress_timeout" json:"imagePullProgressTimeout" reload:"hot"`
+	// DefaultPullPolicy overrides the image pull policy used for sandboxes scheduled
+	// onto this runtime class when a container does not specify its own. One of
+	// `always`, `missing`, or `never`, matching containers/common semantics. Empty
+	// means the CRI request's own policy (or kubelet default) applies unchanged.
+	DefaultPullPolicy string `toml:"default_pull_policy" json:"defaultPullPolicy"`
+	// PullQPS overrides the rate, in queries per second, at which image pulls are
+	// throttled for sandboxes scheduled onto this runtime class. Zero means unthrottled.
+	PullQPS float64 `toml:"pull_qps" json:"pullQPS"`
+	// PullBurst overrides the burst size allowed above PullQPS for this runtime class.
+	PullBurst int `toml:"pull_burst" json:"pullBurst"`
+	// Registry overrides the top-level Registry for sandboxes scheduled onto this
+	// runtime class, so e.g. a `kata`/`gvisor` runtime handling untrusted workloads
+	// can pin different mirrors, auth credentials, or config_path than `runc`. The
+	// effective registry is resolved by overlaying this on top of the global one;
+	// see EffectiveRegistry.
+	Registry Registry `toml:"registry" json:"registry"`
 }
 
 // ContainerdConfig contains toml config related to containerd
@@ -98,7 +134,7 @@ type ContainerdConfig struct {
 	// Snapshotter is the snapshotter used by containerd.
 	Snapshotter string `toml:"snapshotter" json:"snapshotter"`
 	// DefaultRuntimeName is the default runtime name to use from the runtimes table.
-	DefaultRuntimeName string `toml:"default_runtime_name" json:"defaultRuntimeName"`
+	DefaultRuntimeName string `toml:"default_runtime_name" json:"defaultRuntimeName" reload:"restart"`
 	// DefaultRuntime is the default runtime to use in containerd.
 	// This runtime is used when no runtime handler (or the empty string) is provided.
 	// DEPRECATED: use DefaultRuntimeName instead. Remove in containerd 1.4.
@@ -174,6 +210,26 @@ type Mirror struct {
 	// with host specified.
 	// The scheme, host and path from the endpoint URL will be used.
 	Endpoints []string `toml:"endpoint" json:"endpoint"`
+	// Cache contains config for a pull-through local cache fronting this mirror's endpoints.
+	Cache Cache `toml:"cache" json:"cache"`
+}
+
+// Cache contains the config for a pull-through mirror's local content cache.
+type Cache struct {
+	// Dir is the content-addressable cache directory, keyed by digest, kept
+	// separate from containerd's main content store.
+	Dir string `toml:"dir" json:"dir"`
+	// MaxSize is the upper bound, in bytes, of the cache directory. Once
+	// exceeded, the least recently used entries are evicted.
+	MaxSize int64 `toml:"max_size" json:"maxSize"`
+	// TTL is the maximum duration a cached entry may be served without being
+	// revalidated against the upstream mirror. The string is in the golang
+	// duration format, see: https://golang.org/pkg/time/#ParseDuration
+	TTL string `toml:"ttl" json:"ttl"`
+	// Shared indicates the cache directory is mounted by multiple nodes in a
+	// host pool, so concurrent fetches of the same digest are coordinated
+	// with file locks to hit the upstream at most once per digest.
+	Shared bool `toml:"shared" json:"shared"`
 }
 
 // AuthConfig contains the config related to authentication to a specific registry
@@ -203,7 +259,7 @@ type Registry struct {
 	// ConfigPath is a path to the root directory containing registry-specific
 	// configurations.
 	// If ConfigPath is set, the rest of the registry specific options are ignored.
-	ConfigPath string `toml:"config_path" json:"configPath"`
+	ConfigPath string `toml:"config_path" json:"configPath" reload:"hot"`
 	// Mirrors are namespace to mirror mapping for all namespaces.
 	// This option will not be used when ConfigPath is provided.
 	// DEPRECATED: Use ConfigPath instead. Remove in containerd 2.0.
@@ -211,7 +267,7 @@ type Registry struct {
 	// Configs are configs for each registry.
 	// The key is the domain name or IP of the registry.
 	// DEPRECATED: Use ConfigPath instead.
-	Configs map[string]RegistryConfig `toml:"configs" json:"configs"`
+	Configs map[string]RegistryConfig `toml:"configs" json:"configs" reload:"hot"`
 	// Auths are registry endpoint to auth config mapping. The registry endpoint must
 	// be a valid url with host specified.
 	// DEPRECATED: Use ConfigPath instead. Remove in containerd 1.6.
@@ -231,6 +287,140 @@ type RegistryConfig struct {
 	TLS *TLSConfig `toml:"tls" json:"tls"`
 }
 
+// isZeroRegistry reports whether r has no fields set, so per-runtime registry
+// validation/overlay can be skipped for runtimes that don't override it.
+func isZeroRegistry(r Registry) bool {
+	return r.ConfigPath == "" && len(r.Mirrors) == 0 && len(r.Configs) == 0 &&
+		len(r.Auths) == 0 && len(r.Headers) == 0
+}
+
+// validateRegistry applies the mirrors-vs-config_path exclusivity check,
+// the deprecated configs.tls mapping warning, and the deprecated auths→configs
+// mapping to a single Registry. It is used for both the top-level
+// PluginConfig.Registry and any per-runtime Registry overrides.
+func validateRegistry(ctx context.Context, r *Registry) error {
+	useConfigPath := r.ConfigPath != ""
+	if len(r.Mirrors) > 0 {
+		if useConfigPath {
+			return errors.New("`mirrors` cannot be set when `config_path` is provided")
+		}
+		log.G(ctx).Warning("`mirrors` is deprecated, please use `config_path` instead")
+	}
+	for namespace, mirror := range r.Mirrors {
+		if mirror.Cache.Dir == "" {
+			continue
+		}
+		if mirror.Cache.TTL != "" {
+			if _, err := time.ParseDuration(mirror.Cache.TTL); err != nil {
+				return fmt.Errorf("invalid `cache.ttl` for mirror %q: %w", namespace, err)
+			}
+		}
+		if mirror.Cache.MaxSize < 0 {
+			return fmt.Errorf("`cache.max_size` for mirror %q must not be negative", namespace)
+		}
+	}
+	var hasDeprecatedTLS bool
+	for _, cfg := range r.Configs {
+		if cfg.TLS != nil {
+			hasDeprecatedTLS = true
+			break
+		}
+	}
+	if hasDeprecatedTLS {
+		if useConfigPath {
+			return errors.New("`configs.tls` cannot be set when `config_path` is provided")
+		}
+		log.G(ctx).Warning("`configs.tls` is deprecated, please use `config_path` instead")
+	}
+
+	// Validation for deprecated auths options and mapping it to configs.
+	if len(r.Auths) != 0 {
+		if r.Configs == nil {
+			r.Configs = make(map[string]RegistryConfig)
+		}
+		for endpoint, auth := range r.Auths {
+			auth := auth
+			u, err := url.Parse(endpoint)
+			if err != nil {
+				return fmt.Errorf("failed to parse registry url %q from `registry.auths`: %w", endpoint, err)
+			}
+			if u.Scheme != "" {
+				// Do not include the scheme in the new registry config.
+				endpoint = u.Host
+			}
+			config := r.Configs[endpoint]
+			config.Auth = &auth
+			r.Configs[endpoint] = config
+		}
+		log.G(ctx).Warning("`auths` is deprecated, please use `configs` instead")
+	}
+	return nil
+}
+
+// EffectiveRegistry resolves the Registry that applies to a PullImage call
+// tagged with the given CRI RuntimeHandler, overlaying that runtime's
+// Registry overrides (if any) on top of the global PluginConfig.Registry.
+// A non-empty ConfigPath or Headers on the runtime override replaces the
+// corresponding global field wholesale; Mirrors, Configs and Auths are
+// shallow-merged with the runtime's entries taking precedence on key collision.
+func EffectiveRegistry(c *PluginConfig, runtimeHandler string) Registry {
+	effective := c.Registry
+	r, ok := c.ContainerdConfig.Runtimes[runtimeHandler]
+	if !ok || isZeroRegistry(r.Registry) {
+		return effective
+	}
+	override := r.Registry
+	if override.ConfigPath != "" {
+		effective.ConfigPath = override.ConfigPath
+	}
+	if len(override.Mirrors) > 0 {
+		effective.Mirrors = mergeMirrors(effective.Mirrors, override.Mirrors)
+	}
+	if len(override.Configs) > 0 {
+		effective.Configs = mergeRegistryConfigs(effective.Configs, override.Configs)
+	}
+	if len(override.Auths) > 0 {
+		effective.Auths = mergeAuths(effective.Auths, override.Auths)
+	}
+	if len(override.Headers) > 0 {
+		effective.Headers = override.Headers
+	}
+	return effective
+}
+
+func mergeMirrors(base, override map[string]Mirror) map[string]Mirror {
+	merged := make(map[string]Mirror, len(base)+len(override))
+	for k, v := range base {
+		merged[k] = v
+	}
+	for k, v := range override {
+		merged[k] = v
+	}
+	return merged
+}
+
+func mergeRegistryConfigs(base, override map[string]RegistryConfig) map[string]RegistryConfig {
+	merged := make(map[string]RegistryConfig, len(base)+len(override))
+	for k, v := range base {
+		merged[k] = v
+	}
+	for k, v := range override {
+		merged[k] = v
+	}
+	return merged
+}
+
+func mergeAuths(base, override map[string]AuthConfig) map[string]AuthConfig {
+	merged := make(map[string]AuthConfig, len(base)+len(override))
+	for k, v := range base {
+		merged[k] = v
+	}
+	for k, v := range override {
+		merged[k] = v
+	}
+	return merged
+}
+
 // ImageDecryption contains configuration to handling decryption of encrypted container images.
 type ImageDecryption struct {
 	// KeyModel specifies the trust model of where keys should reside.
@@ -243,6 +433,49 @@ type ImageDecryption struct {
 	KeyModel string `toml:"key_model" json:"keyModel"`
 }
 
+// ImageVerification contains the config for verifying image signatures
+// before unpacking, using the containers/image-style policy schema (a
+// top-level `default` plus per-scope rules of type `insecureAcceptAnything`,
+// `reject`, `signedBy`, or `sigstoreSigned`). See ParsePolicy and
+// ScopeRequirements in imagepolicy.go.
+type ImageVerification struct {
+	// PolicyPath is the path to the signature verification policy file.
+	// Defaults to /etc/containerd/policy.json.
+	PolicyPath string `toml:"policy_path" json:"policyPath"`
+	// RegistriesDPath is the path to a registries.d-style directory mapping
+	// registries/repositories to the signature storage locations (e.g. a
+	// sigstore lookaside directory or rekor endpoint) used to find detached
+	// signatures for a given image reference.
+	RegistriesDPath string `toml:"registries_d_path" json:"registriesDPath"`
+}
+
+// BlockIO contains the config for the blockio class loader.
+type BlockIO struct {
+	// ConfigFile is the path to the blockio class configuration file,
+	// mapping blockio class names to weight, per-device weight, and
+	// throttle read/write bps/iops settings.
+	ConfigFile string `toml:"config_file" json:"configFile"`
+	// Reload enables watching ConfigFile and the set of block devices under
+	// /sys/block for changes, re-resolving blockio classes and updating live
+	// containers without a containerd restart.
+	Reload bool `toml:"reload" json:"reload"`
+}
+
+// IrqBalance contains the config for coordinating irqbalance's banned CPU
+// set with latency-sensitive pods that have been granted exclusive CPUs.
+type IrqBalance struct {
+	// ConfigFile is the path to irqbalance's sysconfig-style configuration
+	// file (e.g. /etc/sysconfig/irqbalance) whose IRQBALANCE_BANNED_CPUS
+	// mask is edited to exclude exclusive CPUs of latency-sensitive pods.
+	ConfigFile string `toml:"config_file" json:"configFile"`
+	// RestoreFile is where the original IRQBALANCE_BANNED_CPUS mask is
+	// snapshotted once at startup, so upgrades or crashes don't lose the
+	// baseline mask that should be restored when no pods need exclusivity.
+	RestoreFile string `toml:"restore_file" json:"restoreFile"`
+	// Enabled turns on irqbalance banned-CPU coordination.
+	Enabled bool `toml:"enabled" json:"enabled"`
+}
+
 // PluginConfig contains toml config related to CRI plugin,
 // it is a subset of Config.
 type PluginConfig struct {
@@ -254,6 +487,15 @@ type PluginConfig struct {
 	Registry Registry `toml:"registry" json:"registry"`
 	// ImageDecryption contains config related to handling decryption of encrypted container images
 	ImageDecryption `toml:"image_decryption" json:"imageDecryption"`
+	// BlockIO contains config for the blockio class loader, applied to containers
+	// carrying the `blockio.resources.beta.kubernetes.io/class` annotation.
+	BlockIO BlockIO `toml:"blockio" json:"blockio"`
+	// IrqBalance contains config for coordinating irqbalance's banned CPU set
+	// with latency-sensitive pods granted exclusive CPUs.
+	IrqBalance IrqBalance `toml:"irqbalance" json:"irqbalance"`
+	// ImageVerification contains config for verifying image signatures
+	// against a policy before unpacking.
+	ImageVerification ImageVerification `toml:"image_verification" json:"imageVerification"`
 	// DisableTCPService disables serving CRI on the TCP server.
 	DisableTCPService bool `toml:"disable_tcp_service" json:"disableTCPService"`
 	// StreamServerAddress is the ip address streaming server is listening on.
@@ -286,6 +528,10 @@ type PluginConfig struct {
 	// Log line longer than the limit will be split into multiple lines. Non-positive
 	// value means no limit.
 	MaxContainerLogLineSize int `toml:"max_container_log_line_size" json:"maxContainerLogSize"`
+	// LogSizeMax is the maximum size in bytes of a container's log file before
+	// the log writer in cri/io rotates or truncates it. A value of -1 (the default)
+	// means no limit.
+	LogSizeMax int64 `toml:"log_size_max" json:"logSizeMax"`
 	// DisableCgroup indicates to disable the cgroup support.
 	// This is useful when the containerd does not have permission to access cgroup.
 	DisableCgroup bool `toml:"disable_cgroup" json:"disableCgroup"`
@@ -312,6 +558,10 @@ type PluginConfig struct {
 	// present in /sys/fs/cgroup/cgroup.controllers.
 	// This helps with running rootless mode + cgroup v2 + systemd but without hugetlb delegation.
 	DisableHugetlbController bool `toml:"disable_hugetlb_controller" json:"disableHugetlbController"`
+	// TolerateMissingPidsController if set to false makes ValidatePluginConfig reject any runtime
+	// with a positive `pids_limit` if the pids cgroup controller is not present.
+	// This helps with supporting hosts where the pids controller has been disabled. (default is `true`)
+	TolerateMissingPidsController bool `toml:"tolerate_missing_pids_controller" json:"tolerateMissingPidsController"`
 	// DeviceOwnershipFromSecurityContext changes the default behavior of setting container devices uid/gid
 	// from CRI's SecurityContext (RunAsUser/RunAsGroup) instead of taking host's uid/gid. Defaults to false.
 	DeviceOwnershipFromSecurityContext bool `toml:"device_ownership_from_security_context" json:"device_ownership_from_security_context"`
@@ -350,7 +600,7 @@ type PluginConfig struct {
 	//
 	// The string is in the golang duration format, see:
 	//   https://golang.org/pkg/time/#ParseDuration
-	ImagePullProgressTimeout string `toml:"image_pull_progress_timeout" json:"imagePullProgressTimeout"`
+	ImagePullProgressTimeout string `toml:"image_pull_progress_timeout" json:"imagePullProgressTimeout" reload:"hot"`
 	// DrainExecSyncIOTimeout is the maximum duration to wait for ExecSync
 	// API' IO EOF event after exec init process exits. A zero value means
 	// there is no timeout.
@@ -360,6 +610,22 @@ type PluginConfig struct {
 	//
 	// For example, the value can be '5h', '2h30m', '10s'.
 	DrainExecSyncIOTimeout string `toml:"drain_exec_sync_io_timeout" json:"drainExecSyncIOTimeout"`
+	// MaxContainerTerminationMessageLength is the upper bound, in bytes, of the
+	// termination message read from a container's `terminationMessagePath` file.
+	// Defaults to 4096 (4KiB), matching kubelet's default.
+	MaxContainerTerminationMessageLength int `toml:"max_container_termination_message_length" json:"maxContainerTerminationMessageLength"`
+	// MaxContainerTerminationMessageLogLength is the upper bound, in bytes, of the
+	// container log tail used to populate the termination message when
+	// `terminationMessagePolicy` is `FallbackToLogsOnError`. Defaults to 2048 (2KiB).
+	MaxContainerTerminationMessageLogLength int `toml:"max_container_termination_message_log_length" json:"maxContainerTerminationMessageLogLength"`
+	// MaxContainerTerminationMessageLogLines caps the number of lines read from the
+	// tail of a container's log when falling back to logs for the termination message.
+	MaxContainerTerminationMessageLogLines int `toml:"max_container_termination_message_log_lines" json:"maxContainerTerminationMessageLogLines"`
+	// MaxPodTerminationMessageLogLength is the upper bound, in bytes, of the
+	// aggregated termination message across all containers in a pod. When the
+	// combined size would exceed this, each container's message is truncated
+	// evenly so the total stays under the limit. Defaults to 12288 (12KiB).
+	MaxPodTerminationMessageLogLength int `toml:"max_pod_termination_message_log_length" json:"maxPodTerminationMessageLogLength"`
 }
 
 // X509KeyPairStreaming contains the x509 configuration for streaming
@@ -393,8 +659,33 @@ const (
 	// KeyModelNode is the key model where key for encrypted images reside
 	// on the worker nodes
 	KeyModelNode = "node"
+	// PullPolicyAlways always pulls the image, even if it is already present locally.
+	PullPolicyAlways = "always"
+	// PullPolicyMissing only pulls the image if it is not already present locally.
+	PullPolicyMissing = "missing"
+	// PullPolicyNever never pulls the image, failing if it is not already present locally.
+	PullPolicyNever = "never"
 )
 
+// pidsControllerAvailable reports whether the pids cgroup controller is
+// present on this host, checking both cgroup v1 (a dedicated `pids`
+// hierarchy) and cgroup v2 (a `pids` entry in the unified controllers file).
+func pidsControllerAvailable() bool {
+	if _, err := os.Stat("/sys/fs/cgroup/pids"); err == nil {
+		return true
+	}
+	data, err := os.ReadFile("/sys/fs/cgroup/cgroup.controllers")
+	if err != nil {
+		return false
+	}
+	for _, controller := range strings.Fields(string(data)) {
+		if controller == "pids" {
+			return true
+		}
+	}
+	return false
+}
+
 // ValidatePluginConfig validates the given plugin configuration.
 func ValidatePluginConfig(ctx context.Context, c *PluginConfig) error {
 	if c.ContainerdConfig.Runtimes == nil {
@@ -455,6 +746,31 @@ func ValidatePluginConfig(ctx context.Context, c *PluginConfig) error {
 		if !r.PrivilegedWithoutHostDevices && r.PrivilegedWithoutHostDevicesAllDevicesAllowed {
 			return errors.New("`privileged_without_host_devices_all_devices_allowed` requires `privileged_without_host_devices` to be enabled")
 		}
+		if r.PidsLimit < -1 {
+			return fmt.Errorf("`pids_limit` for runtime %q must be -1 (unlimited) or greater", k)
+		}
+		if r.PidsLimit > 0 && !pidsControllerAvailable() && !c.TolerateMissingPidsController {
+			return fmt.Errorf("`pids_limit` for runtime %q requires the pids cgroup controller, which is not available", k)
+		}
+		if r.MaxConcurrentDownloads < 0 {
+			return fmt.Errorf("`max_concurrent_downloads` for runtime %q must not be negative", k)
+		}
+		if r.ImagePullProgressTimeout != "" {
+			if _, err := time.ParseDuration(r.ImagePullProgressTimeout); err != nil {
+				return fmt.Errorf("invalid `image_pull_progress_timeout` for runtime %q: %w", k, err)
+			}
+		}
+		switch r.DefaultPullPolicy {
+		case "", PullPolicyAlways, PullPolicyMissing, PullPolicyNever:
+		default:
+			return fmt.Errorf("invalid `default_pull_policy` for runtime %q: %q", k, r.DefaultPullPolicy)
+		}
+		if r.PullQPS < 0 {
+			return fmt.Errorf("`pull_qps` for runtime %q must not be negative", k)
+		}
+		if r.PullBurst < 0 {
+			return fmt.Errorf("`pull_burst` for runtime %q must not be negative", k)
+		}
 		// If empty, use default podSandbox mode
 		if len(r.SandboxMode) == 0 {
 			r.SandboxMode = string(ModePodSandbox)
@@ -462,47 +778,28 @@ func ValidatePluginConfig(ctx context.Context, c *PluginConfig) error {
 		}
 	}
 
-	useConfigPath := c.Registry.ConfigPath != ""
-	if len(c.Registry.Mirrors) > 0 {
-		if useConfigPath {
-			return errors.New("`mirrors` cannot be set when `config_path` is provided")
-		}
-		log.G(ctx).Warning("`mirrors` is deprecated, please use `config_path` instead")
+	if err := validateRegistry(ctx, &c.Registry); err != nil {
+		return err
 	}
-	var hasDeprecatedTLS bool
-	for _, r := range c.Registry.Configs {
-		if r.TLS != nil {
-			hasDeprecatedTLS = true
-			break
+	for k, r := range c.ContainerdConfig.Runtimes {
+		if isZeroRegistry(r.Registry) {
+			continue
 		}
-	}
-	if hasDeprecatedTLS {
-		if useConfigPath {
-			return errors.New("`configs.tls` cannot be set when `config_path` is provided")
+		// A runtime that sets `mirrors` without its own `config_path` still
+		// inherits the global `config_path` via EffectiveRegistry, so the
+		// same exclusivity check applies against the inherited value, not
+		// just the runtime's own (possibly empty) field.
+		effectiveConfigPath := r.Registry.ConfigPath
+		if effectiveConfigPath == "" {
+			effectiveConfigPath = c.Registry.ConfigPath
 		}
-		log.G(ctx).Warning("`configs.tls` is deprecated, please use `config_path` instead")
-	}
-
-	// Validation for deprecated auths options and mapping it to configs.
-	if len(c.Registry.Auths) != 0 {
-		if c.Registry.Configs == nil {
-			c.Registry.Configs = make(map[string]RegistryConfig)
+		if len(r.Registry.Mirrors) > 0 && effectiveConfigPath != "" {
+			return fmt.Errorf("runtime %q: `mirrors` cannot be set when `config_path` is provided", k)
 		}
-		for endpoint, auth := range c.Registry.Auths {
-			auth := auth
-			u, err := url.Parse(endpoint)
-			if err != nil {
-				return fmt.Errorf("failed to parse registry url %q from `registry.auths`: %w", endpoint, err)
-			}
-			if u.Scheme != "" {
-				// Do not include the scheme in the new registry config.
-				endpoint = u.Host
-			}
-			config := c.Registry.Configs[endpoint]
-			config.Auth = &auth
-			c.Registry.Configs[endpoint] = config
+		if err := validateRegistry(ctx, &r.Registry); err != nil {
+			return fmt.Errorf("runtime %q: %w", k, err)
 		}
-		log.G(ctx).Warning("`auths` is deprecated, please use `configs` instead")
+		c.ContainerdConfig.Runtimes[k] = r
 	}
 
 	// Validation for stream_idle_timeout
@@ -525,5 +822,39 @@ func ValidatePluginConfig(ctx context.Context, c *PluginConfig) error {
 			return fmt.Errorf("invalid `drain_exec_sync_io_timeout`: %w", err)
 		}
 	}
+
+	// Validation for termination message limits.
+	if c.MaxContainerTerminationMessageLength < 0 {
+		return errors.New("`max_container_termination_message_length` must not be negative")
+	}
+	if c.MaxContainerTerminationMessageLogLength < 0 {
+		return errors.New("`max_container_termination_message_log_length` must not be negative")
+	}
+	if c.MaxContainerTerminationMessageLogLines < 0 {
+		return errors.New("`max_container_termination_message_log_lines` must not be negative")
+	}
+	if c.MaxPodTerminationMessageLogLength < 0 {
+		return errors.New("`max_pod_termination_message_log_length` must not be negative")
+	}
+
+	// Validation for log_size_max.
+	if c.LogSizeMax < -1 {
+		return errors.New("`log_size_max` must be -1 (unlimited) or greater")
+	}
+
+	// Validation for blockio.
+	if c.BlockIO.Reload && c.BlockIO.ConfigFile == "" {
+		return errors.New("`blockio.reload` requires `blockio.config_file` to be set")
+	}
+
+	// Validation for irqbalance.
+	if c.IrqBalance.Enabled && c.IrqBalance.ConfigFile == "" {
+		return errors.New("`irqbalance.enabled` requires `irqbalance.config_file` to be set")
+	}
+
+	// Default image_verification.policy_path.
+	if c.ImageVerification.PolicyPath == "" {
+		c.ImageVerification.PolicyPath = "/etc/containerd/policy.json"
+	}
 	return nil
 }
@@ -0,0 +1,213 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package config
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+func TestValidateRegistryRejectsMirrorsWithConfigPath(t *testing.T) {
+	r := &Registry{
+		ConfigPath: "/etc/containerd/certs.d",
+		Mirrors:    map[string]Mirror{"docker.io": {Endpoints: []string{"https://mirror.example.com"}}},
+	}
+	if err := validateRegistry(context.Background(), r); err == nil {
+		t.Fatal("expected error when `mirrors` and `config_path` are both set")
+	}
+}
+
+func TestValidateRegistryRejectsDeprecatedTLSWithConfigPath(t *testing.T) {
+	r := &Registry{
+		ConfigPath: "/etc/containerd/certs.d",
+		Configs: map[string]RegistryConfig{
+			"docker.io": {TLS: &TLSConfig{CAFile: "ca.pem"}},
+		},
+	}
+	if err := validateRegistry(context.Background(), r); err == nil {
+		t.Fatal("expected error when `configs.tls` and `config_path` are both set")
+	}
+}
+
+func TestValidateRegistryAllowsMirrorsAlone(t *testing.T) {
+	r := &Registry{
+		Mirrors: map[string]Mirror{"docker.io": {Endpoints: []string{"https://mirror.example.com"}}},
+	}
+	if err := validateRegistry(context.Background(), r); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestValidateRegistryRejectsNegativeCacheMaxSize(t *testing.T) {
+	r := &Registry{
+		Mirrors: map[string]Mirror{
+			"docker.io": {Cache: Cache{Dir: "/var/lib/containerd/mirror-cache", MaxSize: -1}},
+		},
+	}
+	if err := validateRegistry(context.Background(), r); err == nil {
+		t.Fatal("expected error for negative cache.max_size")
+	}
+}
+
+func TestValidateRegistryRejectsInvalidCacheTTL(t *testing.T) {
+	r := &Registry{
+		Mirrors: map[string]Mirror{
+			"docker.io": {Cache: Cache{Dir: "/var/lib/containerd/mirror-cache", TTL: "not-a-duration"}},
+		},
+	}
+	if err := validateRegistry(context.Background(), r); err == nil {
+		t.Fatal("expected error for invalid cache.ttl")
+	}
+}
+
+func TestValidateRegistryMapsDeprecatedAuthsToConfigs(t *testing.T) {
+	r := &Registry{
+		Auths: map[string]AuthConfig{
+			"https://registry.example.com": {Username: "u", Password: "p"},
+		},
+	}
+	if err := validateRegistry(context.Background(), r); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	cfg, ok := r.Configs["registry.example.com"]
+	if !ok {
+		t.Fatalf("expected auths entry mapped into configs without scheme, got %v", r.Configs)
+	}
+	if cfg.Auth == nil || cfg.Auth.Username != "u" {
+		t.Fatalf("expected mapped auth to carry over username, got %v", cfg.Auth)
+	}
+}
+
+func TestValidatePluginConfigRejectsPerRuntimeMirrorsWithGlobalConfigPath(t *testing.T) {
+	c := &PluginConfig{}
+	c.ContainerdConfig.DefaultRuntimeName = "runc"
+	c.Registry.ConfigPath = "/etc/containerd/certs.d"
+	c.ContainerdConfig.Runtimes = map[string]Runtime{
+		"runc": {
+			Type: "io.containerd.runc.v2",
+			Registry: Registry{
+				Mirrors: map[string]Mirror{"docker.io": {Endpoints: []string{"https://mirror.example.com"}}},
+			},
+		},
+	}
+	err := ValidatePluginConfig(context.Background(), c)
+	if err == nil {
+		t.Fatal("expected error: per-runtime `mirrors` conflicts with the global `config_path`")
+	}
+}
+
+func TestValidatePluginConfigAllowsPerRuntimeMirrorsWithoutGlobalConfigPath(t *testing.T) {
+	c := &PluginConfig{}
+	c.ContainerdConfig.DefaultRuntimeName = "runc"
+	c.ContainerdConfig.Runtimes = map[string]Runtime{
+		"runc": {
+			Type: "io.containerd.runc.v2",
+			Registry: Registry{
+				Mirrors: map[string]Mirror{"docker.io": {Endpoints: []string{"https://mirror.example.com"}}},
+			},
+		},
+	}
+	if err := ValidatePluginConfig(context.Background(), c); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestEffectiveRegistryUnknownRuntimeReturnsGlobal(t *testing.T) {
+	c := &PluginConfig{}
+	c.Registry.ConfigPath = "/etc/containerd/certs.d"
+	got := EffectiveRegistry(c, "missing")
+	if !reflect.DeepEqual(got, c.Registry) {
+		t.Fatalf("expected global registry unchanged, got %+v", got)
+	}
+}
+
+func TestEffectiveRegistryRuntimeWithNoOverrideReturnsGlobal(t *testing.T) {
+	c := &PluginConfig{}
+	c.Registry.ConfigPath = "/etc/containerd/certs.d"
+	c.ContainerdConfig.Runtimes = map[string]Runtime{
+		"runc": {Type: "io.containerd.runc.v2"},
+	}
+	got := EffectiveRegistry(c, "runc")
+	if !reflect.DeepEqual(got, c.Registry) {
+		t.Fatalf("expected global registry unchanged for runtime with no override, got %+v", got)
+	}
+}
+
+func TestEffectiveRegistryScalarOverrideReplacesWholesale(t *testing.T) {
+	c := &PluginConfig{}
+	c.Registry.ConfigPath = "/etc/containerd/certs.d"
+	c.ContainerdConfig.Runtimes = map[string]Runtime{
+		"kata": {
+			Type:     "io.containerd.kata.v2",
+			Registry: Registry{ConfigPath: "/etc/containerd/certs.d/kata"},
+		},
+	}
+	got := EffectiveRegistry(c, "kata")
+	if got.ConfigPath != "/etc/containerd/certs.d/kata" {
+		t.Fatalf("expected runtime config_path to replace global, got %q", got.ConfigPath)
+	}
+}
+
+func TestEffectiveRegistryMapOverrideShallowMergesWithRuntimePrecedence(t *testing.T) {
+	c := &PluginConfig{}
+	c.Registry.Mirrors = map[string]Mirror{
+		"docker.io": {Endpoints: []string{"https://global-mirror.example.com"}},
+		"quay.io":   {Endpoints: []string{"https://quay-mirror.example.com"}},
+	}
+	c.ContainerdConfig.Runtimes = map[string]Runtime{
+		"kata": {
+			Type: "io.containerd.kata.v2",
+			Registry: Registry{
+				Mirrors: map[string]Mirror{
+					"docker.io": {Endpoints: []string{"https://kata-mirror.example.com"}},
+				},
+			},
+		},
+	}
+	got := EffectiveRegistry(c, "kata")
+	if len(got.Mirrors) != 2 {
+		t.Fatalf("expected shallow merge to keep untouched global keys, got %v", got.Mirrors)
+	}
+	if !reflect.DeepEqual(got.Mirrors["docker.io"].Endpoints, []string{"https://kata-mirror.example.com"}) {
+		t.Fatalf("expected runtime override to win on key collision, got %v", got.Mirrors["docker.io"])
+	}
+	if !reflect.DeepEqual(got.Mirrors["quay.io"].Endpoints, []string{"https://quay-mirror.example.com"}) {
+		t.Fatalf("expected global-only key to survive the merge, got %v", got.Mirrors["quay.io"])
+	}
+}
+
+func TestEffectiveRegistryDoesNotMutateGlobalConfig(t *testing.T) {
+	c := &PluginConfig{}
+	c.Registry.Mirrors = map[string]Mirror{
+		"docker.io": {Endpoints: []string{"https://global-mirror.example.com"}},
+	}
+	c.ContainerdConfig.Runtimes = map[string]Runtime{
+		"kata": {
+			Type: "io.containerd.kata.v2",
+			Registry: Registry{
+				Mirrors: map[string]Mirror{
+					"docker.io": {Endpoints: []string{"https://kata-mirror.example.com"}},
+				},
+			},
+		},
+	}
+	_ = EffectiveRegistry(c, "kata")
+	if !reflect.DeepEqual(c.Registry.Mirrors["docker.io"].Endpoints, []string{"https://global-mirror.example.com"}) {
+		t.Fatalf("expected global config to remain unchanged, got %v", c.Registry.Mirrors["docker.io"])
+	}
+}
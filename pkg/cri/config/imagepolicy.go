@@ -0,0 +1,210 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// PolicyIdentity constrains which image identity a signedBy/sigstoreSigned
+// requirement accepts, mirroring the containers/image signedIdentity field.
+type PolicyIdentity struct {
+	// Type is one of matchExact, matchRepository, exactReference, or
+	// exactRepository.
+	Type string `json:"type"`
+	// DockerReference is required when Type is exactReference or
+	// exactRepository, naming the reference/repository the signature's
+	// identity must equal.
+	DockerReference string `json:"dockerReference,omitempty"`
+}
+
+// PolicyRequirement is a single rule from a policy.json scope's requirement
+// list. The requirement list for a scope is evaluated as a logical AND: an
+// image is allowed only if every requirement in the list is satisfied.
+type PolicyRequirement struct {
+	// Type is one of insecureAcceptAnything, reject, signedBy, or
+	// sigstoreSigned.
+	Type string `json:"type"`
+	// KeyType is required for signedBy, e.g. GPGKeys or signedByGPGKeys.
+	KeyType string `json:"keyType,omitempty"`
+	// KeyPath and KeyData are mutually exclusive ways of supplying the
+	// signing key(s) for signedBy; exactly one must be set.
+	KeyPath string `json:"keyPath,omitempty"`
+	KeyData string `json:"keyData,omitempty"`
+	// SignedIdentity restricts which image identity a signedBy/
+	// sigstoreSigned requirement accepts. Defaults to matchExact when omitted.
+	SignedIdentity *PolicyIdentity `json:"signedIdentity,omitempty"`
+	// FulcioCAPath and RekorPublicKeyPath are used by sigstoreSigned to
+	// validate Fulcio-issued certificates and Rekor transparency log entries.
+	FulcioCAPath       string `json:"fulcioCAPath,omitempty"`
+	RekorPublicKeyPath string `json:"rekorPublicKeyPath,omitempty"`
+}
+
+// Policy is a parsed containers/image-style policy.json document: a
+// mandatory default requirement list, plus optional per-transport,
+// per-scope overrides. The docker transport's scopes are registry,
+// repository, or repository:tag strings.
+type Policy struct {
+	Default    []PolicyRequirement                       `json:"default"`
+	Transports map[string]map[string][]PolicyRequirement `json:"transports,omitempty"`
+}
+
+var validRequirementTypes = map[string]bool{
+	"insecureAcceptAnything": true,
+	"reject":                 true,
+	"signedBy":               true,
+	"sigstoreSigned":         true,
+}
+
+var validIdentityTypes = map[string]bool{
+	"matchExact":      true,
+	"matchRepository": true,
+	"exactReference":  true,
+	"exactRepository": true,
+}
+
+// ParsePolicy parses a policy.json document, validating that every
+// requirement list is non-empty and that every requirement has a recognized
+// type with the fields that type requires.
+func ParsePolicy(data []byte) (*Policy, error) {
+	var p Policy
+	if err := json.Unmarshal(data, &p); err != nil {
+		return nil, fmt.Errorf("failed to parse policy document: %w", err)
+	}
+	if len(p.Default) == 0 {
+		return nil, fmt.Errorf("policy document must set a non-empty `default` requirement list")
+	}
+	if err := validateRequirements(p.Default, "default"); err != nil {
+		return nil, err
+	}
+	for transport, scopes := range p.Transports {
+		for scope, reqs := range scopes {
+			if len(reqs) == 0 {
+				return nil, fmt.Errorf("transports[%q][%q] must be a non-empty requirement list", transport, scope)
+			}
+			if err := validateRequirements(reqs, fmt.Sprintf("transports[%q][%q]", transport, scope)); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return &p, nil
+}
+
+func validateRequirements(reqs []PolicyRequirement, path string) error {
+	for i, r := range reqs {
+		if !validRequirementTypes[r.Type] {
+			return fmt.Errorf("%s[%d]: unrecognized requirement type %q", path, i, r.Type)
+		}
+		if r.Type == "signedBy" {
+			if r.KeyType == "" {
+				return fmt.Errorf("%s[%d]: signedBy requires `keyType`", path, i)
+			}
+			if (r.KeyPath == "") == (r.KeyData == "") {
+				return fmt.Errorf("%s[%d]: signedBy requires exactly one of `keyPath` or `keyData`", path, i)
+			}
+		}
+		if r.Type == "sigstoreSigned" {
+			if r.KeyPath == "" && r.FulcioCAPath == "" {
+				return fmt.Errorf("%s[%d]: sigstoreSigned requires `keyPath` or `fulcioCAPath`", path, i)
+			}
+			if r.FulcioCAPath != "" && r.RekorPublicKeyPath == "" {
+				return fmt.Errorf("%s[%d]: sigstoreSigned with `fulcioCAPath` also requires `rekorPublicKeyPath`", path, i)
+			}
+		}
+		if r.SignedIdentity != nil {
+			if !validIdentityTypes[r.SignedIdentity.Type] {
+				return fmt.Errorf("%s[%d]: unrecognized signedIdentity type %q", path, i, r.SignedIdentity.Type)
+			}
+			needsRef := r.SignedIdentity.Type == "exactReference" || r.SignedIdentity.Type == "exactRepository"
+			if needsRef && r.SignedIdentity.DockerReference == "" {
+				return fmt.Errorf("%s[%d]: signedIdentity type %q requires `dockerReference`", path, i, r.SignedIdentity.Type)
+			}
+		}
+	}
+	return nil
+}
+
+// ScopeRequirements resolves the requirement list that applies to a docker
+// transport image reference, using containers/image's scope specificity
+// rules: an exact repository:tag match wins, then the bare repository, then
+// progressively shorter path prefixes (for namespaced registries), then the
+// registry host alone, and finally the policy-wide default.
+func ScopeRequirements(policy *Policy, transport, scope string) []PolicyRequirement {
+	scopes := policy.Transports[transport]
+	for _, candidate := range scopeCandidates(scope) {
+		if reqs, ok := scopes[candidate]; ok {
+			return reqs
+		}
+	}
+	return policy.Default
+}
+
+// scopeCandidates returns scope, then scope with any :tag stripped, then
+// each successively shorter slash-delimited prefix, most specific first.
+func scopeCandidates(scope string) []string {
+	candidates := []string{scope}
+	withoutTag := scope
+	if i := strings.LastIndex(scope, ":"); i > strings.LastIndex(scope, "/") {
+		withoutTag = scope[:i]
+		candidates = append(candidates, withoutTag)
+	}
+	for {
+		i := strings.LastIndex(withoutTag, "/")
+		if i < 0 {
+			break
+		}
+		withoutTag = withoutTag[:i]
+		candidates = append(candidates, withoutTag)
+	}
+	return candidates
+}
+
+// EvaluateRequirements decides whether an image identity satisfies a scope's
+// requirement list, which containers/image treats as a logical AND: every
+// requirement must be satisfied. insecureAcceptAnything and reject are
+// decided without any external input; signedBy and sigstoreSigned delegate
+// to verifySignature, which should attempt to find and cryptographically
+// verify a matching signature for that requirement (fetched from the
+// registry's signature storage or a sigstore rekor lookup) and is left to
+// the caller since it requires network access and crypto this package does
+// not perform. Returns the first unsatisfied requirement's reason.
+func EvaluateRequirements(reqs []PolicyRequirement, verifySignature func(PolicyRequirement) (bool, string, error)) (bool, string, error) {
+	for _, r := range reqs {
+		switch r.Type {
+		case "insecureAcceptAnything":
+			continue
+		case "reject":
+			return false, "image rejected by policy", nil
+		case "signedBy", "sigstoreSigned":
+			if verifySignature == nil {
+				return false, "", fmt.Errorf("policy requires %q but no signature verifier was supplied", r.Type)
+			}
+			ok, reason, err := verifySignature(r)
+			if err != nil {
+				return false, "", err
+			}
+			if !ok {
+				return false, reason, nil
+			}
+		default:
+			return false, "", fmt.Errorf("unrecognized requirement type %q", r.Type)
+		}
+	}
+	return true, "", nil
+}
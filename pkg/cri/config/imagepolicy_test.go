@@ -0,0 +1,220 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package config
+
+import (
+	"errors"
+	"testing"
+)
+
+var errFixtureVerifier = errors.New("fixture verifier failure")
+
+func TestParsePolicyAcceptsMinimalDocument(t *testing.T) {
+	data := []byte(`{"default": [{"type": "insecureAcceptAnything"}]}`)
+	p, err := ParsePolicy(data)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(p.Default) != 1 || p.Default[0].Type != "insecureAcceptAnything" {
+		t.Fatalf("unexpected default: %+v", p.Default)
+	}
+}
+
+func TestParsePolicyRejectsMissingDefault(t *testing.T) {
+	if _, err := ParsePolicy([]byte(`{"transports": {}}`)); err == nil {
+		t.Fatal("expected error for missing `default`")
+	}
+}
+
+func TestParsePolicyRejectsEmptyDefault(t *testing.T) {
+	if _, err := ParsePolicy([]byte(`{"default": []}`)); err == nil {
+		t.Fatal("expected error for empty `default`")
+	}
+}
+
+func TestParsePolicyRejectsUnrecognizedRequirementType(t *testing.T) {
+	data := []byte(`{"default": [{"type": "bogus"}]}`)
+	if _, err := ParsePolicy(data); err == nil {
+		t.Fatal("expected error for unrecognized requirement type")
+	}
+}
+
+func TestParsePolicyRejectsSignedByMissingKeyType(t *testing.T) {
+	data := []byte(`{"default": [{"type": "signedBy", "keyPath": "/keys/a.gpg"}]}`)
+	if _, err := ParsePolicy(data); err == nil {
+		t.Fatal("expected error for signedBy missing keyType")
+	}
+}
+
+func TestParsePolicyRejectsSignedByWithBothKeyPathAndKeyData(t *testing.T) {
+	data := []byte(`{"default": [{"type": "signedBy", "keyType": "GPGKeys", "keyPath": "/a", "keyData": "YQ=="}]}`)
+	if _, err := ParsePolicy(data); err == nil {
+		t.Fatal("expected error for signedBy with both keyPath and keyData")
+	}
+}
+
+func TestParsePolicyRejectsSignedByWithNeitherKeyPathNorKeyData(t *testing.T) {
+	data := []byte(`{"default": [{"type": "signedBy", "keyType": "GPGKeys"}]}`)
+	if _, err := ParsePolicy(data); err == nil {
+		t.Fatal("expected error for signedBy with neither keyPath nor keyData")
+	}
+}
+
+func TestParsePolicyRejectsSigstoreSignedMissingKeySource(t *testing.T) {
+	data := []byte(`{"default": [{"type": "sigstoreSigned"}]}`)
+	if _, err := ParsePolicy(data); err == nil {
+		t.Fatal("expected error for sigstoreSigned missing keyPath/fulcioCAPath")
+	}
+}
+
+func TestParsePolicyRejectsFulcioWithoutRekor(t *testing.T) {
+	data := []byte(`{"default": [{"type": "sigstoreSigned", "fulcioCAPath": "/fulcio-ca.pem"}]}`)
+	if _, err := ParsePolicy(data); err == nil {
+		t.Fatal("expected error for fulcioCAPath without rekorPublicKeyPath")
+	}
+}
+
+func TestParsePolicyAcceptsSigstoreSignedWithFulcioAndRekor(t *testing.T) {
+	data := []byte(`{"default": [{"type": "sigstoreSigned", "fulcioCAPath": "/fulcio-ca.pem", "rekorPublicKeyPath": "/rekor.pub"}]}`)
+	if _, err := ParsePolicy(data); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestParsePolicyRejectsExactReferenceSignedIdentityWithoutDockerReference(t *testing.T) {
+	data := []byte(`{"default": [{"type": "signedBy", "keyType": "GPGKeys", "keyPath": "/a", "signedIdentity": {"type": "exactReference"}}]}`)
+	if _, err := ParsePolicy(data); err == nil {
+		t.Fatal("expected error for exactReference signedIdentity missing dockerReference")
+	}
+}
+
+func TestParsePolicyRejectsEmptyTransportScopeList(t *testing.T) {
+	data := []byte(`{"default": [{"type": "insecureAcceptAnything"}], "transports": {"docker": {"docker.io": []}}}`)
+	if _, err := ParsePolicy(data); err == nil {
+		t.Fatal("expected error for empty transport scope requirement list")
+	}
+}
+
+func exampleScopedPolicy(t *testing.T) *Policy {
+	t.Helper()
+	data := []byte(`{
+		"default": [{"type": "reject"}],
+		"transports": {
+			"docker": {
+				"docker.io": [{"type": "insecureAcceptAnything"}],
+				"docker.io/library/nginx": [{"type": "signedBy", "keyType": "GPGKeys", "keyPath": "/keys/nginx.gpg"}],
+				"quay.io/org": [{"type": "signedBy", "keyType": "GPGKeys", "keyPath": "/keys/org.gpg"}]
+			}
+		}
+	}`)
+	p, err := ParsePolicy(data)
+	if err != nil {
+		t.Fatalf("failed to parse fixture policy: %v", err)
+	}
+	return p
+}
+
+func TestScopeRequirementsExactRepositoryMatchWinsOverRegistry(t *testing.T) {
+	p := exampleScopedPolicy(t)
+	reqs := ScopeRequirements(p, "docker", "docker.io/library/nginx:1.25")
+	if len(reqs) != 1 || reqs[0].Type != "signedBy" {
+		t.Fatalf("expected the repository-specific signedBy rule to win, got %+v", reqs)
+	}
+}
+
+func TestScopeRequirementsFallsBackToRegistryScope(t *testing.T) {
+	p := exampleScopedPolicy(t)
+	reqs := ScopeRequirements(p, "docker", "docker.io/library/redis:7")
+	if len(reqs) != 1 || reqs[0].Type != "insecureAcceptAnything" {
+		t.Fatalf("expected the registry-wide rule to apply, got %+v", reqs)
+	}
+}
+
+func TestScopeRequirementsFallsBackToNamespacePrefix(t *testing.T) {
+	p := exampleScopedPolicy(t)
+	reqs := ScopeRequirements(p, "docker", "quay.io/org/sub/app:latest")
+	if len(reqs) != 1 || reqs[0].Type != "signedBy" {
+		t.Fatalf("expected the quay.io/org prefix rule to apply, got %+v", reqs)
+	}
+}
+
+func TestScopeRequirementsFallsBackToDefault(t *testing.T) {
+	p := exampleScopedPolicy(t)
+	reqs := ScopeRequirements(p, "docker", "ghcr.io/someone/unrelated:latest")
+	if len(reqs) != 1 || reqs[0].Type != "reject" {
+		t.Fatalf("expected the policy default to apply, got %+v", reqs)
+	}
+}
+
+func TestEvaluateRequirementsInsecureAcceptAnythingAllows(t *testing.T) {
+	ok, reason, err := EvaluateRequirements([]PolicyRequirement{{Type: "insecureAcceptAnything"}}, nil)
+	if err != nil || !ok || reason != "" {
+		t.Fatalf("expected allow with no reason, got ok=%v reason=%q err=%v", ok, reason, err)
+	}
+}
+
+func TestEvaluateRequirementsRejectDenies(t *testing.T) {
+	ok, reason, err := EvaluateRequirements([]PolicyRequirement{{Type: "reject"}}, nil)
+	if err != nil || ok || reason == "" {
+		t.Fatalf("expected deny with a reason, got ok=%v reason=%q err=%v", ok, reason, err)
+	}
+}
+
+func TestEvaluateRequirementsSignedByWithoutVerifierErrors(t *testing.T) {
+	reqs := []PolicyRequirement{{Type: "signedBy", KeyType: "GPGKeys", KeyPath: "/a"}}
+	if _, _, err := EvaluateRequirements(reqs, nil); err == nil {
+		t.Fatal("expected error when signedBy is required but no verifier was supplied")
+	}
+}
+
+func TestEvaluateRequirementsAllMustPass(t *testing.T) {
+	reqs := []PolicyRequirement{
+		{Type: "signedBy", KeyType: "GPGKeys", KeyPath: "/a"},
+		{Type: "signedBy", KeyType: "GPGKeys", KeyPath: "/b"},
+	}
+	calls := 0
+	verify := func(r PolicyRequirement) (bool, string, error) {
+		calls++
+		if r.KeyPath == "/b" {
+			return false, "no signature matched key /b", nil
+		}
+		return true, "", nil
+	}
+	ok, reason, err := EvaluateRequirements(reqs, verify)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if ok {
+		t.Fatal("expected overall denial when one of two AND'd requirements fails")
+	}
+	if reason != "no signature matched key /b" {
+		t.Fatalf("expected the failing requirement's reason, got %q", reason)
+	}
+	if calls != 2 {
+		t.Fatalf("expected both requirements to be evaluated up to the failure, got %d calls", calls)
+	}
+}
+
+func TestEvaluateRequirementsPropagatesVerifierError(t *testing.T) {
+	reqs := []PolicyRequirement{{Type: "sigstoreSigned", FulcioCAPath: "/ca", RekorPublicKeyPath: "/rekor"}}
+	verify := func(PolicyRequirement) (bool, string, error) {
+		return false, "", errFixtureVerifier
+	}
+	if _, _, err := EvaluateRequirements(reqs, verify); err != errFixtureVerifier {
+		t.Fatalf("expected verifier error to propagate unchanged, got %v", err)
+	}
+}
@@ -0,0 +1,152 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package config
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// irqBalanceBannedCPUsKey is the sysconfig key irqbalance reads its banned
+// CPU mask from (e.g. in /etc/sysconfig/irqbalance).
+const irqBalanceBannedCPUsKey = "IRQBALANCE_BANNED_CPUS"
+
+// ParseCPUMask parses an IRQBALANCE_BANNED_CPUS-style mask: one or more
+// 32-bit hex groups separated by commas, most-significant group first (the
+// same format cpumask_parse produces), into the set of CPU indices it bans.
+func ParseCPUMask(mask string) (map[int]bool, error) {
+	banned := map[int]bool{}
+	mask = strings.TrimSpace(mask)
+	if mask == "" {
+		return banned, nil
+	}
+	groups := strings.Split(mask, ",")
+	numGroups := len(groups)
+	for i, group := range groups {
+		group = strings.TrimSpace(group)
+		v, err := strconv.ParseUint(group, 16, 32)
+		if err != nil {
+			return nil, fmt.Errorf("invalid cpu mask group %q: %w", group, err)
+		}
+		// groups[0] is the most-significant (highest CPU numbers); the base
+		// CPU index of this group is (numGroups-1-i)*32.
+		base := (numGroups - 1 - i) * 32
+		for bit := 0; bit < 32; bit++ {
+			if v&(1<<uint(bit)) != 0 {
+				banned[base+bit] = true
+			}
+		}
+	}
+	return banned, nil
+}
+
+// FormatCPUMask renders the set of banned CPU indices back into
+// IRQBALANCE_BANNED_CPUS format, covering CPUs 0..maxCPU inclusive.
+func FormatCPUMask(banned map[int]bool, maxCPU int) string {
+	numGroups := maxCPU/32 + 1
+	groups := make([]string, numGroups)
+	for g := 0; g < numGroups; g++ {
+		var v uint32
+		base := g * 32
+		for bit := 0; bit < 32; bit++ {
+			if banned[base+bit] {
+				v |= 1 << uint(bit)
+			}
+		}
+		// groups are emitted most-significant first, so group g (low CPUs
+		// for g=0) goes at the end of the output slice.
+		groups[numGroups-1-g] = fmt.Sprintf("%08x", v)
+	}
+	return strings.Join(groups, ",")
+}
+
+// ComputeBannedMask returns the IRQBALANCE_BANNED_CPUS mask that results
+// from banning (ban=true) or restoring (ban=false) cpus on top of
+// originalMask. maxCPU must be at least the highest CPU index that may ever
+// appear in cpus or originalMask, so the formatted mask is wide enough.
+func ComputeBannedMask(originalMask string, cpus []int, ban bool, maxCPU int) (string, error) {
+	banned, err := ParseCPUMask(originalMask)
+	if err != nil {
+		return "", err
+	}
+	for _, cpu := range cpus {
+		if cpu > maxCPU {
+			maxCPU = cpu
+		}
+		if ban {
+			banned[cpu] = true
+		} else {
+			delete(banned, cpu)
+		}
+	}
+	return FormatCPUMask(banned, maxCPU), nil
+}
+
+// EditIrqBalanceConfig updates the IRQBALANCE_BANNED_CPUS line of an
+// irqbalance sysconfig file's contents, banning or restoring cpus relative
+// to whatever mask (if any) is already present, and returns the rewritten
+// file contents. If the key is absent, it is appended. Other lines are
+// preserved verbatim.
+func EditIrqBalanceConfig(content string, cpus []int, ban bool, maxCPU int) (string, error) {
+	lines := strings.Split(content, "\n")
+	found := false
+	for i, line := range lines {
+		key, value, ok := splitSysconfigLine(line)
+		if !ok || key != irqBalanceBannedCPUsKey {
+			continue
+		}
+		found = true
+		newMask, err := ComputeBannedMask(value, cpus, ban, maxCPU)
+		if err != nil {
+			return "", err
+		}
+		lines[i] = fmt.Sprintf("%s=%q", irqBalanceBannedCPUsKey, newMask)
+	}
+	if !found {
+		newMask, err := ComputeBannedMask("", cpus, ban, maxCPU)
+		if err != nil {
+			return "", err
+		}
+		entry := fmt.Sprintf("%s=%q", irqBalanceBannedCPUsKey, newMask)
+		if len(lines) > 0 && lines[len(lines)-1] == "" {
+			// Preserve the trailing newline: insert before the final empty element.
+			lines = append(lines[:len(lines)-1], entry, "")
+		} else {
+			lines = append(lines, entry)
+		}
+	}
+	return strings.Join(lines, "\n"), nil
+}
+
+// splitSysconfigLine splits a `KEY=value` or `KEY="value"` sysconfig line
+// into its key and unquoted value. It returns ok=false for blank lines,
+// comments, or lines without an '=' separator.
+func splitSysconfigLine(line string) (key, value string, ok bool) {
+	trimmed := strings.TrimSpace(line)
+	if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+		return "", "", false
+	}
+	idx := strings.Index(trimmed, "=")
+	if idx < 0 {
+		return "", "", false
+	}
+	key = strings.TrimSpace(trimmed[:idx])
+	value = strings.TrimSpace(trimmed[idx+1:])
+	value = strings.Trim(value, `"`)
+	return key, value, true
+}
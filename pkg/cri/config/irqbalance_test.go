@@ -0,0 +1,105 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package config
+
+import "testing"
+
+func TestParseAndFormatCPUMaskRoundTrip(t *testing.T) {
+	banned, err := ParseCPUMask("00000000,00000005")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !banned[0] || !banned[2] {
+		t.Fatalf("expected cpus 0 and 2 banned, got %v", banned)
+	}
+	if len(banned) != 2 {
+		t.Fatalf("expected exactly 2 banned cpus, got %v", banned)
+	}
+	if got := FormatCPUMask(banned, 33); got != "00000000,00000005" {
+		t.Fatalf("expected round-tripped mask, got %q", got)
+	}
+}
+
+func TestParseCPUMaskEmpty(t *testing.T) {
+	banned, err := ParseCPUMask("")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(banned) != 0 {
+		t.Fatalf("expected no banned cpus, got %v", banned)
+	}
+}
+
+func TestParseCPUMaskInvalid(t *testing.T) {
+	if _, err := ParseCPUMask("not-hex"); err == nil {
+		t.Fatal("expected error for invalid mask group")
+	}
+}
+
+func TestComputeBannedMaskBanAndRestore(t *testing.T) {
+	masked, err := ComputeBannedMask("00000000", []int{4, 5}, true, 31)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if masked != "00000030" {
+		t.Fatalf("expected cpus 4,5 banned, got %q", masked)
+	}
+
+	restored, err := ComputeBannedMask(masked, []int{4}, false, 31)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if restored != "00000020" {
+		t.Fatalf("expected only cpu 5 still banned, got %q", restored)
+	}
+}
+
+func TestEditIrqBalanceConfigUpdatesExistingKey(t *testing.T) {
+	content := "IRQBALANCE_ARGS=\"\"\nIRQBALANCE_BANNED_CPUS=\"00000000\"\n"
+	updated, err := EditIrqBalanceConfig(content, []int{2, 3}, true, 31)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := "IRQBALANCE_ARGS=\"\"\nIRQBALANCE_BANNED_CPUS=\"0000000c\"\n"
+	if updated != want {
+		t.Fatalf("got %q, want %q", updated, want)
+	}
+}
+
+func TestEditIrqBalanceConfigAppendsMissingKey(t *testing.T) {
+	content := "IRQBALANCE_ARGS=\"\"\n"
+	updated, err := EditIrqBalanceConfig(content, []int{0}, true, 31)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := "IRQBALANCE_ARGS=\"\"\nIRQBALANCE_BANNED_CPUS=\"00000001\"\n"
+	if updated != want {
+		t.Fatalf("got %q, want %q", updated, want)
+	}
+}
+
+func TestEditIrqBalanceConfigRestoreToZeroKeepsKey(t *testing.T) {
+	content := "IRQBALANCE_BANNED_CPUS=\"00000001\"\n"
+	updated, err := EditIrqBalanceConfig(content, []int{0}, false, 31)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := "IRQBALANCE_BANNED_CPUS=\"00000000\"\n"
+	if updated != want {
+		t.Fatalf("got %q, want %q", updated, want)
+	}
+}
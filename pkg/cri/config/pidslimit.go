@@ -0,0 +1,37 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package config
+
+// EffectivePidsLimit resolves the pids limit that should be applied to a
+// container, given the Runtime's configured PidsLimit and the value (if
+// any) the CRI request's LinuxContainerResources.PidsLimit carried.
+// A containerRequested value of 0 means the request did not set one, so the
+// runtime's default applies; any positive value overrides the runtime
+// default, per-container, as CRI allows.
+func EffectivePidsLimit(runtimeDefault, containerRequested int64) int64 {
+	if containerRequested > 0 {
+		return containerRequested
+	}
+	return runtimeDefault
+}
+
+// ShouldRotateLog reports whether a container log writer enforcing
+// LogSizeMax should rotate or truncate the log now that it has grown to
+// currentSize. A non-positive maxSize (the default, -1) disables the limit.
+func ShouldRotateLog(currentSize, maxSize int64) bool {
+	return maxSize > 0 && currentSize >= maxSize
+}
@@ -0,0 +1,53 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package config
+
+import "testing"
+
+func TestEffectivePidsLimitContainerOverridesRuntime(t *testing.T) {
+	if got := EffectivePidsLimit(-1, 256); got != 256 {
+		t.Fatalf("expected container-requested limit to win, got %d", got)
+	}
+}
+
+func TestEffectivePidsLimitFallsBackToRuntimeDefault(t *testing.T) {
+	if got := EffectivePidsLimit(1024, 0); got != 1024 {
+		t.Fatalf("expected runtime default when container did not request a limit, got %d", got)
+	}
+}
+
+func TestShouldRotateLog(t *testing.T) {
+	cases := []struct {
+		name        string
+		currentSize int64
+		maxSize     int64
+		want        bool
+	}{
+		{"under limit", 10, 100, false},
+		{"at limit", 100, 100, true},
+		{"over limit", 200, 100, true},
+		{"unlimited", 1 << 30, -1, false},
+		{"zero disables", 1 << 30, 0, false},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			if got := ShouldRotateLog(c.currentSize, c.maxSize); got != c.want {
+				t.Fatalf("got %v, want %v", got, c.want)
+			}
+		})
+	}
+}
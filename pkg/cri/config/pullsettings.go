@@ -0,0 +1,63 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package config
+
+// PullSettings is the resolved set of image-pull knobs that apply to a
+// sandbox scheduled onto a particular runtime handler, after overlaying
+// that Runtime's overrides on top of the PluginConfig defaults.
+type PullSettings struct {
+	MaxConcurrentDownloads   int
+	ImagePullProgressTimeout string
+	DefaultPullPolicy        string
+	PullQPS                  float64
+	PullBurst                int
+}
+
+// EffectivePullSettings resolves the PullSettings that apply to a
+// PullImage/EnsureImageExists call for the given CRI RuntimeHandler,
+// overlaying that Runtime's MaxConcurrentDownloads, ImagePullProgressTimeout,
+// DefaultPullPolicy, PullQPS and PullBurst (when set) on top of the
+// PluginConfig-wide defaults. An unknown or unset runtimeHandler returns the
+// PluginConfig defaults unchanged.
+func EffectivePullSettings(c *PluginConfig, runtimeHandler string) PullSettings {
+	settings := PullSettings{
+		MaxConcurrentDownloads:   c.MaxConcurrentDownloads,
+		ImagePullProgressTimeout: c.ImagePullProgressTimeout,
+		PullQPS:                  0,
+		PullBurst:                0,
+	}
+	r, ok := c.ContainerdConfig.Runtimes[runtimeHandler]
+	if !ok {
+		return settings
+	}
+	if r.MaxConcurrentDownloads > 0 {
+		settings.MaxConcurrentDownloads = r.MaxConcurrentDownloads
+	}
+	if r.ImagePullProgressTimeout != "" {
+		settings.ImagePullProgressTimeout = r.ImagePullProgressTimeout
+	}
+	if r.DefaultPullPolicy != "" {
+		settings.DefaultPullPolicy = r.DefaultPullPolicy
+	}
+	if r.PullQPS > 0 {
+		settings.PullQPS = r.PullQPS
+	}
+	if r.PullBurst > 0 {
+		settings.PullBurst = r.PullBurst
+	}
+	return settings
+}
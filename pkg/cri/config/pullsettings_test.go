@@ -0,0 +1,57 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package config
+
+import "testing"
+
+func TestEffectivePullSettingsUnknownRuntimeReturnsDefaults(t *testing.T) {
+	c := &PluginConfig{}
+	c.MaxConcurrentDownloads = 3
+	c.ImagePullProgressTimeout = "5m"
+	got := EffectivePullSettings(c, "missing")
+	if got.MaxConcurrentDownloads != 3 || got.ImagePullProgressTimeout != "5m" {
+		t.Fatalf("unexpected defaults: %+v", got)
+	}
+}
+
+func TestEffectivePullSettingsOverlaysRuntimeOverrides(t *testing.T) {
+	c := &PluginConfig{}
+	c.MaxConcurrentDownloads = 10
+	c.ImagePullProgressTimeout = "5m"
+	c.ContainerdConfig.Runtimes = map[string]Runtime{
+		"kata": {
+			MaxConcurrentDownloads:   1,
+			DefaultPullPolicy:        PullPolicyAlways,
+			PullQPS:                  2.5,
+			PullBurst:                5,
+			ImagePullProgressTimeout: "", // unset: global value should still apply
+		},
+	}
+	got := EffectivePullSettings(c, "kata")
+	if got.MaxConcurrentDownloads != 1 {
+		t.Fatalf("expected runtime override for MaxConcurrentDownloads, got %d", got.MaxConcurrentDownloads)
+	}
+	if got.ImagePullProgressTimeout != "5m" {
+		t.Fatalf("expected global ImagePullProgressTimeout to apply when runtime leaves it unset, got %q", got.ImagePullProgressTimeout)
+	}
+	if got.DefaultPullPolicy != PullPolicyAlways {
+		t.Fatalf("expected runtime DefaultPullPolicy override, got %q", got.DefaultPullPolicy)
+	}
+	if got.PullQPS != 2.5 || got.PullBurst != 5 {
+		t.Fatalf("expected runtime PullQPS/PullBurst override, got %+v", got)
+	}
+}
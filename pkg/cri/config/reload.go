@@ -0,0 +1,150 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package config
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+)
+
+// ReloadPolicy classifies whether a PluginConfig field may be changed by
+// Reload without restarting containerd.
+type ReloadPolicy string
+
+const (
+	// ReloadHot fields may be swapped into the live config by Reload.
+	ReloadHot ReloadPolicy = "hot"
+	// ReloadRestartOnly fields require a containerd restart to take effect;
+	// Reload rejects any change to one of these fields. This is the default
+	// for any field without an explicit `reload:"hot"` struct tag.
+	ReloadRestartOnly ReloadPolicy = "restart"
+)
+
+// fieldReloadPolicy returns the ReloadPolicy for a struct field, defaulting
+// to ReloadRestartOnly when no `reload` tag is present.
+func fieldReloadPolicy(f reflect.StructField) ReloadPolicy {
+	if f.Tag.Get("reload") == string(ReloadHot) {
+		return ReloadHot
+	}
+	return ReloadRestartOnly
+}
+
+// Reload validates candidate against the repo's normal semantic checks, then
+// diffs it field-by-field against current. Candidate is expected to have
+// been decoded from the new TOML but not yet validated: Reload runs
+// ValidatePluginConfig on it, which applies the deprecation mapping
+// (auths→configs, mirrors, untrusted_workload_runtime→runtimes) in place.
+//
+// If any field classified ReloadRestartOnly differs between current and
+// candidate, Reload rejects the reload atomically and returns an error
+// naming the first such field; the caller should keep running with the live
+// config unchanged. Otherwise Reload returns the dotted toml-key paths of
+// every field that did change, so the caller can swap current for candidate
+// under its own lock and emit an event describing what was updated.
+func Reload(ctx context.Context, current, candidate *PluginConfig) ([]string, error) {
+	if err := ValidatePluginConfig(ctx, candidate); err != nil {
+		return nil, fmt.Errorf("candidate config failed validation: %w", err)
+	}
+
+	var restartOnlyChanged string
+	var hotChanged []string
+	diffFields(reflect.ValueOf(*current), reflect.ValueOf(*candidate), "", &hotChanged, &restartOnlyChanged)
+	if restartOnlyChanged != "" {
+		return nil, fmt.Errorf("cannot reload: %q requires a containerd restart to take effect", restartOnlyChanged)
+	}
+	return hotChanged, nil
+}
+
+// diffFields recursively compares two struct values field-by-field,
+// appending the dotted toml-key path of each differing ReloadHot field to
+// *hot, and setting *restartOnly to the path of the first differing
+// ReloadRestartOnly field encountered (recursion stops early once set).
+func diffFields(a, b reflect.Value, path string, hot *[]string, restartOnly *string) {
+	if *restartOnly != "" {
+		return
+	}
+	t := a.Type()
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := f.Tag.Get("toml")
+		if tag == "" || tag == "-" {
+			if f.Anonymous && f.Type.Kind() == reflect.Struct {
+				diffFields(a.Field(i), b.Field(i), path, hot, restartOnly)
+			}
+			continue
+		}
+		fieldPath := tag
+		if path != "" {
+			fieldPath = path + "." + tag
+		}
+		av, bv := a.Field(i), b.Field(i)
+		if f.Type.Kind() == reflect.Struct {
+			diffFields(av, bv, fieldPath, hot, restartOnly)
+			if *restartOnly != "" {
+				return
+			}
+			continue
+		}
+		// A map tagged `reload:"hot"` (e.g. registry.configs) is compared as a
+		// whole below; untagged maps of structs are diffed entry by entry.
+		if f.Type.Kind() == reflect.Map && f.Type.Elem().Kind() == reflect.Struct && fieldReloadPolicy(f) != ReloadHot {
+			diffMapOfStructs(av, bv, fieldPath, hot, restartOnly)
+			if *restartOnly != "" {
+				return
+			}
+			continue
+		}
+		if reflect.DeepEqual(av.Interface(), bv.Interface()) {
+			continue
+		}
+		if fieldReloadPolicy(f) == ReloadHot {
+			*hot = append(*hot, fieldPath)
+		} else {
+			*restartOnly = fieldPath
+			return
+		}
+	}
+}
+
+// diffMapOfStructs recursively diffs a map[string]SomeStruct field key by
+// key, so a per-entry field's own `reload` tag (e.g. Runtime.SandboxMode)
+// governs reloadability even though the map itself carries no tag.
+// An added or removed key is treated as a change to the whole map field.
+func diffMapOfStructs(a, b reflect.Value, path string, hot *[]string, restartOnly *string) {
+	seen := map[string]bool{}
+	for _, k := range a.MapKeys() {
+		key := k.String()
+		seen[key] = true
+		bv := b.MapIndex(k)
+		keyPath := fmt.Sprintf("%s[%s]", path, key)
+		if !bv.IsValid() {
+			*restartOnly = keyPath
+			return
+		}
+		diffFields(a.MapIndex(k), bv, keyPath, hot, restartOnly)
+		if *restartOnly != "" {
+			return
+		}
+	}
+	for _, k := range b.MapKeys() {
+		if !seen[k.String()] {
+			*restartOnly = fmt.Sprintf("%s[%s]", path, k.String())
+			return
+		}
+	}
+}
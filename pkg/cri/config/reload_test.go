@@ -0,0 +1,144 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package config
+
+import (
+	"context"
+	"testing"
+)
+
+func baseTestPluginConfig() *PluginConfig {
+	c := &PluginConfig{}
+	c.ContainerdConfig.DefaultRuntimeName = "runc"
+	c.ContainerdConfig.Runtimes = map[string]Runtime{
+		"runc": {
+			Type:        "io.containerd.runc.v2",
+			SandboxMode: string(ModePodSandbox),
+		},
+	}
+	// Mirrors a config that has already been through ValidatePluginConfig once
+	// (as `current` always has, since it is the live config), so defaulted
+	// fields like image_verification.policy_path don't show up as a spurious
+	// diff purely from candidate being (re-)validated inside Reload.
+	c.ImageVerification.PolicyPath = "/etc/containerd/policy.json"
+	return c
+}
+
+func TestReloadAllowsHotFieldChange(t *testing.T) {
+	current := baseTestPluginConfig()
+	candidate := baseTestPluginConfig()
+	candidate.ImagePullProgressTimeout = "10m"
+
+	changed, err := Reload(context.Background(), current, candidate)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(changed) != 1 || changed[0] != "image_pull_progress_timeout" {
+		t.Fatalf("expected image_pull_progress_timeout reported as changed, got %v", changed)
+	}
+}
+
+func TestReloadRejectsRestartOnlyFieldChange(t *testing.T) {
+	current := baseTestPluginConfig()
+	candidate := baseTestPluginConfig()
+	candidate.Snapshotter = "devmapper"
+
+	changed, err := Reload(context.Background(), current, candidate)
+	if err == nil {
+		t.Fatalf("expected error, got changed=%v", changed)
+	}
+}
+
+func TestReloadAllowsHotChangeOnPerRuntimeMapEntry(t *testing.T) {
+	current := baseTestPluginConfig()
+	candidate := baseTestPluginConfig()
+	r := candidate.ContainerdConfig.Runtimes["runc"]
+	r.SandboxMode = string(ModeShim)
+	candidate.ContainerdConfig.Runtimes["runc"] = r
+
+	changed, err := Reload(context.Background(), current, candidate)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	found := false
+	for _, c := range changed {
+		if c == "containerd.runtimes[runc].sandbox_mode" {
+			found = true
+		}
+	}
+	if !found {
+		t.Fatalf("expected containerd.runtimes[runc].sandbox_mode reported as changed, got %v", changed)
+	}
+}
+
+func TestReloadRejectsRestartOnlyChangeOnPerRuntimeMapEntry(t *testing.T) {
+	current := baseTestPluginConfig()
+	candidate := baseTestPluginConfig()
+	r := candidate.ContainerdConfig.Runtimes["runc"]
+	r.Type = "io.containerd.runsc.v1"
+	candidate.ContainerdConfig.Runtimes["runc"] = r
+
+	if _, err := Reload(context.Background(), current, candidate); err == nil {
+		t.Fatal("expected error for restart-only runtime_type change")
+	}
+}
+
+func TestReloadRejectsAddedRuntimeKey(t *testing.T) {
+	current := baseTestPluginConfig()
+	candidate := baseTestPluginConfig()
+	candidate.ContainerdConfig.Runtimes["kata"] = Runtime{Type: "io.containerd.kata.v2"}
+
+	if _, err := Reload(context.Background(), current, candidate); err == nil {
+		t.Fatal("expected error when a runtime key is added, since that requires a restart")
+	}
+}
+
+func TestReloadRejectsRemovedRuntimeKey(t *testing.T) {
+	current := baseTestPluginConfig()
+	candidate := baseTestPluginConfig()
+	delete(candidate.ContainerdConfig.Runtimes, "runc")
+
+	if _, err := Reload(context.Background(), current, candidate); err == nil {
+		t.Fatal("expected error when a runtime key is removed, since that requires a restart")
+	}
+}
+
+func TestReloadRejectsCandidateFailingValidation(t *testing.T) {
+	current := baseTestPluginConfig()
+	candidate := baseTestPluginConfig()
+	candidate.ContainerdConfig.Snapshotter = ""
+	r := candidate.ContainerdConfig.Runtimes["runc"]
+	r.MaxConcurrentDownloads = -1
+	candidate.ContainerdConfig.Runtimes["runc"] = r
+
+	if _, err := Reload(context.Background(), current, candidate); err == nil {
+		t.Fatal("expected candidate validation failure to surface as an error")
+	}
+}
+
+func TestReloadNoChangesReturnsEmpty(t *testing.T) {
+	current := baseTestPluginConfig()
+	candidate := baseTestPluginConfig()
+
+	changed, err := Reload(context.Background(), current, candidate)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(changed) != 0 {
+		t.Fatalf("expected no changes, got %v", changed)
+	}
+}
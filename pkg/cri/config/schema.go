@@ -0,0 +1,210 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+)
+
+// durationFields are PluginConfig (and nested struct) toml keys whose string
+// value must parse with time.ParseDuration. Schema annotates these with a
+// `durationString` format hint instead of a JSON Schema built-in format.
+var durationFields = map[string]bool{
+	"stream_idle_timeout":         true,
+	"image_pull_progress_timeout": true,
+	"drain_exec_sync_io_timeout":  true,
+	"ttl":                         true,
+}
+
+// enumFields are toml keys restricted to a fixed set of string values.
+var enumFields = map[string][]string{
+	"sandbox_mode":        {string(ModePodSandbox), string(ModeShim)},
+	"default_pull_policy": {"", PullPolicyAlways, PullPolicyMissing, PullPolicyNever},
+}
+
+// Schema returns a JSON Schema (draft-07) document describing the toml
+// structure accepted by PluginConfig, derived from the struct tags on
+// PluginConfig, ContainerdConfig, Runtime, and Registry (and their nested
+// types). Operators and tooling (IDE plugins, admission controllers) can use
+// it to pre-validate /etc/containerd/config.toml before a rollout.
+func Schema() map[string]interface{} {
+	return map[string]interface{}{
+		"$schema":     "http://json-schema.org/draft-07/schema#",
+		"title":       "CRI plugin configuration",
+		"type":        "object",
+		"properties":  schemaProperties(reflect.TypeOf(PluginConfig{})),
+		"definitions": map[string]interface{}{},
+	}
+}
+
+// schemaProperties builds the JSON Schema "properties" object for a struct
+// type by walking its exported fields and `toml` tags, recursing into
+// nested struct and map-of-struct fields.
+func schemaProperties(t reflect.Type) map[string]interface{} {
+	props := map[string]interface{}{}
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := f.Tag.Get("toml")
+		if tag == "" || tag == "-" {
+			if f.Anonymous && f.Type.Kind() == reflect.Struct {
+				for k, v := range schemaProperties(f.Type) {
+					props[k] = v
+				}
+			}
+			continue
+		}
+		props[tag] = schemaProperty(f.Type, tag)
+	}
+	return props
+}
+
+// schemaProperty returns the JSON Schema fragment for a single field's type.
+func schemaProperty(t reflect.Type, tomlKey string) map[string]interface{} {
+	if enum, ok := enumFields[tomlKey]; ok {
+		return map[string]interface{}{"type": "string", "enum": enum}
+	}
+	switch t.Kind() {
+	case reflect.String:
+		prop := map[string]interface{}{"type": "string"}
+		if durationFields[tomlKey] {
+			prop["format"] = "durationString"
+		}
+		return prop
+	case reflect.Bool:
+		return map[string]interface{}{"type": "boolean"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return map[string]interface{}{"type": "integer"}
+	case reflect.Float32, reflect.Float64:
+		return map[string]interface{}{"type": "number"}
+	case reflect.Slice:
+		return map[string]interface{}{"type": "array", "items": schemaProperty(t.Elem(), "")}
+	case reflect.Map:
+		return map[string]interface{}{
+			"type":                 "object",
+			"additionalProperties": schemaProperty(t.Elem(), ""),
+		}
+	case reflect.Ptr:
+		return schemaProperty(t.Elem(), tomlKey)
+	case reflect.Struct:
+		return map[string]interface{}{
+			"type":       "object",
+			"properties": schemaProperties(t),
+		}
+	case reflect.Interface:
+		return map[string]interface{}{}
+	default:
+		return map[string]interface{}{}
+	}
+}
+
+// ValidateAgainstSchema checks raw TOML-as-JSON config bytes against the
+// schema returned by Schema, catching structural mistakes (wrong type for a
+// key, an unrecognized key) before the semantic checks in
+// ValidatePluginConfig run. It does not itself decode TOML; callers are
+// expected to convert the on-disk config.toml to JSON (e.g. via
+// toml.Tree.ToMap plus encoding/json) before calling this.
+func ValidateAgainstSchema(configJSON []byte) error {
+	var raw map[string]interface{}
+	if err := json.Unmarshal(configJSON, &raw); err != nil {
+		return fmt.Errorf("failed to parse config as JSON: %w", err)
+	}
+	schema := Schema()
+	props, _ := schema["properties"].(map[string]interface{})
+	return validateObject(raw, props, "")
+}
+
+// validateObject recursively checks that every key present in obj has a
+// matching entry in props, and that its JSON-decoded value kind matches the
+// declared schema type.
+func validateObject(obj map[string]interface{}, props map[string]interface{}, path string) error {
+	for key, value := range obj {
+		fieldPath := key
+		if path != "" {
+			fieldPath = path + "." + key
+		}
+		prop, ok := props[key]
+		if !ok {
+			return fmt.Errorf("unrecognized config key %q", fieldPath)
+		}
+		propMap, ok := prop.(map[string]interface{})
+		if !ok {
+			continue
+		}
+		if err := validateValue(value, propMap, fieldPath); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validateValue checks a single decoded JSON value against its schema
+// fragment's declared type.
+func validateValue(value interface{}, prop map[string]interface{}, path string) error {
+	wantType, _ := prop["type"].(string)
+	switch wantType {
+	case "object":
+		nested, ok := value.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("config key %q must be a table", path)
+		}
+		if nestedProps, ok := prop["properties"].(map[string]interface{}); ok {
+			return validateObject(nested, nestedProps, path)
+		}
+		if additional, ok := prop["additionalProperties"].(map[string]interface{}); ok {
+			for key, entry := range nested {
+				if err := validateValue(entry, additional, path+"."+key); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	case "array":
+		if _, ok := value.([]interface{}); !ok {
+			return fmt.Errorf("config key %q must be an array", path)
+		}
+		return nil
+	case "string":
+		if _, ok := value.(string); !ok {
+			return fmt.Errorf("config key %q must be a string", path)
+		}
+		if enum, ok := prop["enum"].([]string); ok {
+			s := value.(string)
+			for _, e := range enum {
+				if e == s {
+					return nil
+				}
+			}
+			return fmt.Errorf("config key %q has invalid value %q", path, s)
+		}
+		return nil
+	case "boolean":
+		if _, ok := value.(bool); !ok {
+			return fmt.Errorf("config key %q must be a boolean", path)
+		}
+		return nil
+	case "integer", "number":
+		if _, ok := value.(float64); !ok {
+			return fmt.Errorf("config key %q must be a number", path)
+		}
+		return nil
+	default:
+		return nil
+	}
+}
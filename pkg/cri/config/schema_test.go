@@ -0,0 +1,127 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package config
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSchemaTopLevelShape(t *testing.T) {
+	schema := Schema()
+	if schema["$schema"] != "http://json-schema.org/draft-07/schema#" {
+		t.Fatalf("unexpected $schema: %v", schema["$schema"])
+	}
+	props, ok := schema["properties"].(map[string]interface{})
+	if !ok {
+		t.Fatal("expected properties to be a map")
+	}
+	if _, ok := props["stream_idle_timeout"]; !ok {
+		t.Fatal("expected stream_idle_timeout in top-level properties")
+	}
+	if _, ok := props["containerd"]; !ok {
+		t.Fatal("expected containerd in top-level properties")
+	}
+}
+
+func TestSchemaDurationFieldGetsFormatHint(t *testing.T) {
+	prop := schemaProperty(reflect.TypeOf(""), "image_pull_progress_timeout")
+	if prop["format"] != "durationString" {
+		t.Fatalf("expected durationString format hint, got %v", prop)
+	}
+}
+
+func TestSchemaEnumField(t *testing.T) {
+	prop := schemaProperty(reflect.TypeOf(""), "default_pull_policy")
+	enum, ok := prop["enum"].([]string)
+	if !ok {
+		t.Fatalf("expected enum slice, got %v", prop["enum"])
+	}
+	if len(enum) != 4 {
+		t.Fatalf("expected 4 enum values, got %v", enum)
+	}
+}
+
+func TestSchemaNestedMapOfStruct(t *testing.T) {
+	prop := schemaProperty(reflect.TypeOf(map[string]Runtime{}), "")
+	if prop["type"] != "object" {
+		t.Fatalf("expected object type for map, got %v", prop["type"])
+	}
+	additional, ok := prop["additionalProperties"].(map[string]interface{})
+	if !ok {
+		t.Fatalf("expected additionalProperties to be a schema fragment, got %v", prop["additionalProperties"])
+	}
+	if additional["type"] != "object" {
+		t.Fatalf("expected nested Runtime struct to be an object, got %v", additional["type"])
+	}
+	nestedProps, ok := additional["properties"].(map[string]interface{})
+	if !ok {
+		t.Fatalf("expected nested Runtime properties map, got %v", additional["properties"])
+	}
+	if _, ok := nestedProps["runtime_type"]; !ok {
+		t.Fatal("expected runtime_type among nested Runtime properties")
+	}
+}
+
+func TestValidateAgainstSchemaAcceptsValidConfig(t *testing.T) {
+	j := []byte(`{"stream_idle_timeout": "4h", "containerd": {"runtimes": {"runc": {"sandbox_mode": "podsandbox"}}}}`)
+	if err := ValidateAgainstSchema(j); err != nil {
+		t.Fatalf("expected valid config to pass, got %v", err)
+	}
+}
+
+func TestValidateAgainstSchemaRejectsUnrecognizedKey(t *testing.T) {
+	j := []byte(`{"not_a_real_key": true}`)
+	if err := ValidateAgainstSchema(j); err == nil {
+		t.Fatal("expected error for unrecognized top-level key")
+	}
+}
+
+func TestValidateAgainstSchemaRejectsWrongType(t *testing.T) {
+	j := []byte(`{"stream_idle_timeout": 123}`)
+	if err := ValidateAgainstSchema(j); err == nil {
+		t.Fatal("expected error for wrong type on stream_idle_timeout")
+	}
+}
+
+func TestValidateAgainstSchemaRejectsInvalidEnumValue(t *testing.T) {
+	j := []byte(`{"containerd": {"runtimes": {"runc": {"sandbox_mode": "not-a-real-mode"}}}}`)
+	if err := ValidateAgainstSchema(j); err == nil {
+		t.Fatal("expected error for invalid enum value")
+	}
+}
+
+func TestValidateAgainstSchemaRejectsUnrecognizedNestedKey(t *testing.T) {
+	j := []byte(`{"containerd": {"bogus_nested_key": 1}}`)
+	if err := ValidateAgainstSchema(j); err == nil {
+		t.Fatal("expected error for unrecognized nested key under containerd")
+	}
+}
+
+func TestValidateAgainstSchemaRejectsUnrecognizedKeyInsideMapEntry(t *testing.T) {
+	j := []byte(`{"containerd": {"runtimes": {"runc": {"bogus_runtime_key": 1}}}}`)
+	if err := ValidateAgainstSchema(j); err == nil {
+		t.Fatal("expected error for unrecognized key inside a runtimes map entry")
+	}
+}
+
+func TestValidateAgainstSchemaRejectsNonTableForObjectField(t *testing.T) {
+	j := []byte(`{"containerd": "not-a-table"}`)
+	if err := ValidateAgainstSchema(j); err == nil {
+		t.Fatal("expected error when an object-typed field is given a scalar value")
+	}
+}
@@ -0,0 +1,97 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package config
+
+import (
+	"bytes"
+	"sort"
+)
+
+// TruncateTerminationMessage returns data truncated to at most maxLen bytes,
+// implementing the MaxContainerTerminationMessageLength limit. A non-positive
+// maxLen means no limit; data is returned unmodified.
+func TruncateTerminationMessage(data []byte, maxLen int) []byte {
+	if maxLen <= 0 || len(data) <= maxLen {
+		return data
+	}
+	return data[:maxLen]
+}
+
+// TailLogForTerminationMessage returns the tail of a container log, bounded
+// by both maxBytes (MaxContainerTerminationMessageLogLength) and maxLines
+// (MaxContainerTerminationMessageLogLines). A non-positive bound disables
+// that bound. It is used when terminationMessagePolicy is
+// FallbackToLogsOnError and the terminationMessagePath file was empty on a
+// non-zero exit.
+func TailLogForTerminationMessage(log []byte, maxBytes, maxLines int) []byte {
+	tail := log
+	if maxBytes > 0 && len(tail) > maxBytes {
+		tail = tail[len(tail)-maxBytes:]
+	}
+	if maxLines > 0 {
+		lines := bytes.Split(tail, []byte("\n"))
+		if len(lines) > maxLines {
+			lines = lines[len(lines)-maxLines:]
+		}
+		tail = bytes.Join(lines, []byte("\n"))
+	}
+	return tail
+}
+
+// AggregatePodTerminationMessages evenly truncates each container's
+// termination message so the combined size across the pod never exceeds
+// maxTotal (MaxPodTerminationMessageLogLength), even when there are more
+// containers than there are bytes of budget to go around. maxTotal is
+// divided into one budget per container, sorted by key for deterministic
+// allocation, with the remainder (maxTotal % len(messages)) handed out one
+// byte at a time to the first few containers so every byte of budget is
+// allocated; budget that a shorter message leaves unused is not passed on to
+// other containers. A container whose budget is 0 is truncated to the empty string. A
+// non-positive maxTotal means no limit.
+func AggregatePodTerminationMessages(messages map[string]string, maxTotal int) map[string]string {
+	if maxTotal <= 0 || len(messages) == 0 {
+		return messages
+	}
+	total := 0
+	for _, m := range messages {
+		total += len(m)
+	}
+	if total <= maxTotal {
+		return messages
+	}
+	perContainer := maxTotal / len(messages)
+	extra := maxTotal % len(messages)
+	keys := make([]string, 0, len(messages))
+	for k := range messages {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	out := make(map[string]string, len(messages))
+	for i, k := range keys {
+		budget := perContainer
+		if i < extra {
+			budget++
+		}
+		m := messages[k]
+		if len(m) > budget {
+			out[k] = m[:budget]
+		} else {
+			out[k] = m
+		}
+	}
+	return out
+}
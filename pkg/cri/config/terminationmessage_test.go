@@ -0,0 +1,102 @@
+/*
+   Copyright The containerd Authors.
+
+   Licensed under the Apache License, Version 2.0 (the "License");
+   you may not use this file except in compliance with the License.
+   You may obtain a copy of the License at
+
+       http://www.apache.org/licenses/LICENSE-2.0
+
+   Unless required by applicable law or agreed to in writing, software
+   distributed under the License is distributed on an "AS IS" BASIS,
+   WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+   See the License for the specific language governing permissions and
+   limitations under the License.
+*/
+
+package config
+
+import "testing"
+
+func TestTruncateTerminationMessage(t *testing.T) {
+	if got := string(TruncateTerminationMessage([]byte("hello world"), 5)); got != "hello" {
+		t.Fatalf("got %q", got)
+	}
+	if got := string(TruncateTerminationMessage([]byte("hi"), 5)); got != "hi" {
+		t.Fatalf("short input must be returned unmodified, got %q", got)
+	}
+	if got := string(TruncateTerminationMessage([]byte("hi"), 0)); got != "hi" {
+		t.Fatalf("non-positive maxLen must disable truncation, got %q", got)
+	}
+}
+
+func TestTailLogForTerminationMessage(t *testing.T) {
+	log := []byte("line1\nline2\nline3\nline4\n")
+	got := string(TailLogForTerminationMessage(log, 0, 2))
+	if got != "line4\n" {
+		t.Fatalf("got %q", got)
+	}
+}
+
+func TestTailLogForTerminationMessageByBytes(t *testing.T) {
+	log := []byte("0123456789")
+	got := string(TailLogForTerminationMessage(log, 4, 0))
+	if got != "6789" {
+		t.Fatalf("got %q", got)
+	}
+}
+
+func TestAggregatePodTerminationMessagesUnderLimitUnchanged(t *testing.T) {
+	messages := map[string]string{"a": "short", "b": "also short"}
+	got := AggregatePodTerminationMessages(messages, 1000)
+	if got["a"] != "short" || got["b"] != "also short" {
+		t.Fatalf("messages under the limit must be unchanged, got %v", got)
+	}
+}
+
+func TestAggregatePodTerminationMessagesEvenlyTruncates(t *testing.T) {
+	messages := map[string]string{
+		"a": "aaaaaaaaaa", // 10 bytes
+		"b": "bbbbbbbbbb", // 10 bytes
+	}
+	got := AggregatePodTerminationMessages(messages, 10)
+	if len(got["a"]) != 5 || len(got["b"]) != 5 {
+		t.Fatalf("expected each message truncated to 5 bytes, got %v", got)
+	}
+}
+
+func TestAggregatePodTerminationMessagesDistributesRemainder(t *testing.T) {
+	messages := map[string]string{
+		"a": "aaaaaaaaaa",
+		"b": "bbbbbbbbbb",
+		"c": "cccccccccc",
+	}
+	got := AggregatePodTerminationMessages(messages, 10)
+	total := len(got["a"]) + len(got["b"]) + len(got["c"])
+	if total != 10 {
+		t.Fatalf("expected the full 10-byte budget to be used, got total=%d (%v)", total, got)
+	}
+}
+
+func TestAggregatePodTerminationMessagesNeverExceedsMaxTotalWithManyContainers(t *testing.T) {
+	messages := map[string]string{
+		"a": "aaaaaaaaaa",
+		"b": "bbbbbbbbbb",
+		"c": "cccccccccc",
+		"d": "dddddddddd",
+		"e": "eeeeeeeeee",
+		"f": "ffffffffff",
+		"g": "gggggggggg",
+		"h": "hhhhhhhhhh",
+		"i": "iiiiiiiiii",
+		"j": "jjjjjjjjjj",
+	}
+	got := AggregatePodTerminationMessages(messages, 5)
+	total := 0
+	for _, m := range got {
+		total += len(m)
+	}
+	if total > 5 {
+		t.Fatalf("combined size must never exceed maxTotal even with more containers than budget bytes, got total=%d (%v)", total, got)
+	}
+}